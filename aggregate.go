@@ -0,0 +1,70 @@
+package toil
+
+import (
+	"runtime"
+)
+
+// An Aggregator describes how to summarise items of T into a mergeable aggregate A, such as a sum,
+// a count or a histogram. Zero returns an empty aggregate; Add folds one item, along with its index in
+// the input, into an aggregate; Merge combines two aggregates built from adjacent parts of the input.
+// Merge should be associative and Zero should be its identity.
+// Add may update the aggregate it is given in place, since each one is only ever used by a single fold.
+type Aggregator[T, A any] struct {
+	Zero  func() A
+	Add   func(acc A, i int, x T) (A, error)
+	Merge ReduceFunc[A]
+}
+
+// ParallelAggregate summarises v using agg. The input is split into contiguous blocks which are folded with
+// agg.Add in parallel, and the block aggregates are then combined with agg.Merge as in ParallelReduce.
+// Unlike transforming every item to an A and reducing those, no per-item aggregate is ever allocated.
+// If the slice is empty, agg.Zero() is returned.
+//
+// Absorbing predicates over A set with WithAbsorbing apply to block and partial aggregates.
+// If opts is Deterministic, the blocks have a fixed size and the result does not depend on the worker count.
+// Errors from agg.Add and agg.Merge are wrapped in a *ReduceError. For agg.Add, the last operand is the
+// failing item, after the items of its block folded before it if there are any.
+func ParallelAggregate[T, A any](v []T, agg Aggregator[T, A], opts Options) (A, error) {
+	var zero A
+	absorbing, err := optionFunc[func(A) bool](opts.absorbing)
+	if err != nil {
+		return zero, err
+	}
+	if len(v) == 0 {
+		return agg.Zero(), nil
+	}
+	if opts.workers <= 0 {
+		opts.workers = runtime.NumCPU()
+	}
+
+	size := deterministicBlockSize
+	if !opts.deterministic {
+		size = opts.grain(len(v))
+	}
+
+	partials, absorbed, err := foldBlocks(v, size, opts, func(lo int, block []T) (A, bool, error) {
+		acc := agg.Zero()
+		for i, x := range block {
+			var err error
+			if acc, err = agg.Add(acc, lo+i, x); err != nil {
+				// The operands are the items folded so far in the block, if any, and the failing item
+				operands := [][]Range{{{Lo: lo + i, Hi: lo + i + 1}}}
+				if i > 0 {
+					operands = append([][]Range{{{Lo: lo, Hi: lo + i}}}, operands...)
+				}
+				return acc, false, &ReduceError{Level: 0, Operands: operands, Err: err}
+			}
+			if absorbing != nil && absorbing(acc) {
+				return acc, true, nil
+			}
+		}
+		return acc, false, nil
+	})
+	if err != nil {
+		return zero, err
+	}
+	if absorbed != nil {
+		return *absorbed, nil
+	}
+	return reduceTree(partials, agg.Merge, absorbing, treeLayout{size: size, total: len(v), level: 1}, opts)
+}
@@ -0,0 +1,125 @@
+package toil
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestParallelAggregate_Sum(t *testing.T) {
+	input := make([]int, 10001)
+	expected := 0
+	for i := range input {
+		input[i] = i
+		expected += i
+	}
+	result, err := ParallelAggregate(input, SumOf[int](), Options{}.WithWorkers(4))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result != expected {
+		t.Errorf("Expected %d, got %d", expected, result)
+	}
+}
+
+func TestParallelAggregate_Empty(t *testing.T) {
+	result, err := ParallelAggregate([]int{}, HistogramOf(0, 10), Options{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(result.Counts) != 3 {
+		t.Errorf("Expected an empty histogram with 3 buckets for empty input, got %v", result)
+	}
+}
+
+func TestParallelAggregate_PreservesOrder(t *testing.T) {
+	input := []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"}
+	concat := Aggregator[string, string]{
+		Zero:  func() string { return "" },
+		Add:   func(acc string, _ int, x string) (string, error) { return acc + x, nil },
+		Merge: func(a, b string) (string, error) { return a + b, nil },
+	}
+	for _, workers := range []int{1, 2, 3, 16} {
+		result, err := ParallelAggregate(input, concat, Options{}.WithWorkers(workers))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result != "abcdefghij" {
+			t.Errorf("Workers=%d: expected abcdefghij, got %q", workers, result)
+		}
+	}
+}
+
+func TestParallelAggregate_Error(t *testing.T) {
+	input := make([]int, 100)
+	boom := errors.New("boom")
+	failing := Aggregator[int, int]{
+		Zero: func() int { return 0 },
+		Add: func(acc int, i int, x int) (int, error) {
+			if i == 42 {
+				return acc, boom
+			}
+			return acc + x, nil
+		},
+		Merge: Sum[int],
+	}
+	_, err := ParallelAggregate(input, failing, Options{}.WithWorkers(4))
+	var reduceErr *ReduceError
+	if !errors.As(err, &reduceErr) || !errors.Is(err, boom) {
+		t.Fatalf("Expected *ReduceError wrapping boom, got %v", err)
+	}
+	if last := reduceErr.Operands[len(reduceErr.Operands)-1]; last[0] != (Range{Lo: 42, Hi: 43}) {
+		t.Errorf("Expected failing item [42,43), got %v", reduceErr.Operands)
+	}
+}
+
+func TestParallelAggregate_ErrorAtBlockStart(t *testing.T) {
+	input := []int{1, 2, 3, 4, 5, 6}
+	bad := errors.New("bad")
+	failing := Aggregator[int, int]{
+		Zero: func() int { return 0 },
+		Add: func(acc int, i int, x int) (int, error) {
+			if i == 2 {
+				return acc, bad
+			}
+			return acc + x, nil
+		},
+		Merge: Sum[int],
+	}
+
+	// Item 2 starts the second block, so there is nothing before it to report
+	_, err := ParallelAggregate(input, failing, Options{}.WithWorkers(2).WithGrainSize(2))
+	var reduceErr *ReduceError
+	if !errors.As(err, &reduceErr) || !errors.Is(err, bad) {
+		t.Fatalf("Expected *ReduceError wrapping bad, got %v", err)
+	}
+	if len(reduceErr.Operands) != 1 || reduceErr.Operands[0][0] != (Range{Lo: 2, Hi: 3}) {
+		t.Errorf("Expected only the failing item [2,3), got %v", reduceErr.Operands)
+	}
+	if expected := "toil: reduce failed at level 0 combining [2,3): bad"; err.Error() != expected {
+		t.Errorf("Expected %q, got %q", expected, err.Error())
+	}
+
+	// With blocks of 3 it does not, so the items folded before it are reported too
+	_, err = ParallelAggregate(input, failing, Options{}.WithWorkers(2).WithGrainSize(3))
+	if !errors.As(err, &reduceErr) || len(reduceErr.Operands) != 2 || reduceErr.Operands[0][0] != (Range{Lo: 0, Hi: 2}) {
+		t.Errorf("Expected operands [0,2) [2,3), got %v", err)
+	}
+}
+
+func TestParallelAggregate_DeterministicAcrossWorkers(t *testing.T) {
+	input := floatInput(50001)
+	expected, err := ParallelAggregate(input, MomentsOf[float64](), Options{}.WithWorkers(1).Deterministic(true))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, workers := range []int{2, 7, 32} {
+		result, err := ParallelAggregate(input, MomentsOf[float64](), Options{}.WithWorkers(workers).Deterministic(true))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if math.Float64bits(result.M2) != math.Float64bits(expected.M2) {
+			t.Errorf("Workers=%d: expected %v, got %v", workers, expected, result)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package toil
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// errBreak is returned by the body of forEachBlock to stop starting further blocks, without it being
+// reported as an error, for example once the answer to a search is known.
+var errBreak = errors.New("toil: break")
+
+// a block is a contiguous range [lo, hi) of items; index is its position among the blocks of a call.
+type block struct {
+	index  int
+	lo, hi int
+}
+
+// blockCount returns the number of blocks of size items needed to cover n items.
+func blockCount(n, size int) int {
+	return (n + size - 1) / size
+}
+
+// grain returns the number of items per block for a call over n items: the grain size set with
+// WithGrainSize, or automatically a few blocks per worker, which balances uneven work without paying
+// the scheduling cost for every item or creating too many partial results. o.workers must be resolved.
+func (o Options) grain(n int) int {
+	if o.grainSize > 0 {
+		return o.grainSize
+	}
+	return max(1, (n+4*o.workers-1)/(4*o.workers))
+}
+
+// resolve returns o with the number of workers set for a call over n items: the size of the pool if any,
+// else the number of CPU cores if unset, and a single worker and no pool if n is within the sequential
+// cutoff, so that the call runs inline.
+func (o Options) resolve(n int) Options {
+	if o.pool != nil {
+		o.workers = o.pool.workers
+	}
+	if o.workers <= 0 {
+		o.workers = runtime.NumCPU()
+	}
+	if n <= o.sequentialCutoff {
+		o.workers = 1
+		o.pool = nil
+	}
+	return o
+}
+
+// split is the way a call over n items is cut into blocks, and the order in which workers take them.
+type split struct {
+	n, size  int
+	bounds   []int    // If set, block i is [bounds[i], bounds[i+1]) instead of having size items
+	schedule Schedule // How workers take blocks: StaticSchedule and WorkStealingSchedule, or else in order
+}
+
+// fixedSplit cuts [0, n) into consecutive blocks of size items, started in order.
+func fixedSplit(n, size int) split {
+	return split{n: n, size: size}
+}
+
+// count returns the number of blocks of s.
+func (s split) count() int {
+	if s.bounds != nil {
+		return len(s.bounds) - 1
+	}
+	return blockCount(s.n, s.size)
+}
+
+// ordered reports whether the blocks of s are started in index order when run with opts, so that once one
+// is started, every block not started yet comes after it. On a pool, blocks are queued in order but only
+// check whether to stop once a worker takes them, so a block can be skipped after a later one was started.
+func (s split) ordered(opts Options) bool {
+	return opts.pool == nil && s.schedule != StaticSchedule && s.schedule != WorkStealingSchedule
+}
+
+// at returns the block of s at index.
+func (s split) at(index int) block {
+	if s.bounds != nil {
+		return block{index: index, lo: s.bounds[index], hi: s.bounds[index+1]}
+	}
+	lo := index * s.size
+	return block{index: index, lo: lo, hi: min(lo+s.size, s.n)}
+}
+
+// forEachBlock calls body for each block of s, on up to opts.workers goroutines. Blocks are started in
+// order, unless s is static or work-stealing, see ordered. worker identifies the calling goroutine,
+// from 0 to opts.workers-1, so that body can keep per-worker state without locking.
+// The first error returned by body is returned; if opts has StopOnError set, no further blocks are started.
+// If body returns errBreak, no further blocks are started either, but no error is returned.
+// With a single worker, every block runs in order on the calling goroutine; with a pool, on the pool.
+func forEachBlock(s split, opts Options, body func(worker int, b block) error) error {
+	if opts.workers <= 0 {
+		opts.workers = runtime.NumCPU()
+	}
+	blocks := s.count()
+
+	if opts.pool != nil {
+		return forEachBlockOnPool(s, opts, body)
+	}
+	if opts.workers == 1 {
+		var firstErr error
+		for index := range blocks {
+			err := body(0, s.at(index))
+			if err == errBreak {
+				break
+			}
+			if err != nil && firstErr == nil {
+				firstErr = err
+				if opts.stopOnError {
+					break
+				}
+			}
+		}
+		return firstErr
+	}
+
+	var (
+		wg       sync.WaitGroup
+		next     atomic.Int64 // Index of the next block to start
+		firstErr atomic.Pointer[error]
+		stopped  atomic.Bool // Set once a body returned errBreak
+	)
+	workers := min(opts.workers, blocks)
+
+	// With work stealing, worker w starts with the w-th share of the blocks
+	var queues []blockQueue
+	if s.schedule == WorkStealingSchedule {
+		queues = make([]blockQueue, workers)
+		for w := range queues {
+			queues[w].set(w*blocks/workers, (w+1)*blocks/workers)
+		}
+	}
+
+	for w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := w; ; index += workers {
+				if stopped.Load() || (opts.stopOnError && firstErr.Load() != nil) {
+					return
+				}
+				switch s.schedule {
+				case StaticSchedule:
+					// Worker w runs blocks w, w+workers, ...
+				case WorkStealingSchedule:
+					var ok bool
+					if index, ok = claim(queues, w); !ok {
+						return
+					}
+				default:
+					index = int(next.Add(1) - 1)
+				}
+				if index >= blocks {
+					return
+				}
+				if err := body(w, s.at(index)); err == errBreak {
+					stopped.Store(true)
+				} else if err != nil {
+					firstErr.CompareAndSwap(nil, &err)
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if errPtr := firstErr.Load(); errPtr != nil {
+		return *errPtr
+	}
+	return nil
+}
+
+// forEachBlockOnPool is forEachBlock for opts with a pool: every block is queued on the pool in order,
+// at the priority of the call, and body is given the number of the pool worker running it.
+func forEachBlockOnPool(s split, opts Options, body func(worker int, b block) error) error {
+	var (
+		wg       sync.WaitGroup
+		firstErr atomic.Pointer[error]
+		stopped  atomic.Bool // Set once a body returned errBreak
+	)
+
+	for index := range s.count() {
+		wg.Add(1)
+		err := opts.pool.submit(opts.poolPriority, func(worker int) {
+			defer wg.Done()
+			if stopped.Load() || (opts.stopOnError && firstErr.Load() != nil) {
+				return
+			}
+			if err := body(worker, s.at(index)); err == errBreak {
+				stopped.Store(true)
+			} else if err != nil {
+				firstErr.CompareAndSwap(nil, &err)
+			}
+		})
+		if err != nil {
+			wg.Done()
+			firstErr.CompareAndSwap(nil, &err)
+			break
+		}
+	}
+
+	wg.Wait()
+
+	if errPtr := firstErr.Load(); errPtr != nil {
+		return *errPtr
+	}
+	return nil
+}
+
+// forEachItem is forEachBlock for bodies handling one item at a time. A failed item does not stop the
+// rest of its block unless opts has StopOnError set, in which case every block stops at its next item.
+func forEachItem(s split, opts Options, body func(worker int, b block, i int) error) error {
+	var failed atomic.Bool
+	return forEachBlock(s, opts, func(worker int, b block) error {
+		var blockErr error
+		for i := b.lo; i < b.hi; i++ {
+			if opts.stopOnError && failed.Load() {
+				return blockErr
+			}
+			if err := body(worker, b, i); err != nil {
+				if opts.stopOnError {
+					failed.Store(true)
+					return err
+				}
+				if blockErr == nil {
+					blockErr = err
+				}
+			}
+		}
+		return blockErr
+	})
+}
+
+// concat joins the per-block parts of a result, in block order.
+func concat[T any](parts [][]T) []T {
+	total := 0
+	for _, part := range parts {
+		total += len(part)
+	}
+	out := make([]T, 0, total)
+	for _, part := range parts {
+		out = append(out, part...)
+	}
+	return out
+}
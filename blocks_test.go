@@ -0,0 +1,44 @@
+package toil
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+func TestForEachBlock_CoversAllItems(t *testing.T) {
+	for _, n := range []int{0, 1, 7, 100, 1001} {
+		seen := make([]atomic.Int32, n)
+		err := forEachBlock(fixedSplit(n, 8), Options{}.WithWorkers(3), func(worker int, b block) error {
+			if worker < 0 || worker >= 3 {
+				t.Errorf("Unexpected worker %d", worker)
+			}
+			for i := b.lo; i < b.hi; i++ {
+				seen[i].Add(1)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		for i := range seen {
+			if seen[i].Load() != 1 {
+				t.Fatalf("n=%d: expected item %d to be visited once, got %d", n, i, seen[i].Load())
+			}
+		}
+	}
+}
+
+func TestForEachItem_StopOnError(t *testing.T) {
+	var calls atomic.Int32
+	err := forEachItem(fixedSplit(1000, 10), Options{}.WithWorkers(1).StopOnError(true), func(_ int, _ block, i int) error {
+		calls.Add(1)
+		return errors.New("fail")
+	})
+	if err == nil {
+		t.Fatal("Expected error but got none")
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Expected processing to stop after the first failure, made %d calls", calls.Load())
+	}
+}
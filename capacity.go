@@ -0,0 +1,198 @@
+package toil
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrCapacityExceeded is the error of an item that needs more of a resource than its whole capacity,
+// see WithCapacity and WithResources.
+var ErrCapacityExceeded = errors.New("toil: item weight exceeds capacity")
+
+// amount is an amount of one of the resources of a semaphore, by index.
+type amount struct {
+	resource int
+	n        int64
+}
+
+// semaphore is a weighted semaphore over several resources. An acquire takes all the resources it needs
+// at once or none of them, so acquires never hold some resources while waiting for others and can't
+// deadlock. Waiters are served in order: an acquire doesn't overtake an earlier waiter that needs one of
+// the same resources, even if it would fit, so that heavy acquires are not starved by light ones, while
+// acquires of other resources go ahead.
+type semaphore struct {
+	mu      sync.Mutex
+	size    []int64
+	used    []int64
+	waiters []semaphoreWaiter
+}
+
+type semaphoreWaiter struct {
+	need  []amount
+	ready chan struct{} // Closed once the waiter holds what it needs
+}
+
+func newSemaphore(size []int64) *semaphore {
+	return &semaphore{size: size, used: make([]int64, len(size))}
+}
+
+// acquire waits for need to be free and takes it. Every amount must be at most the size of its resource.
+func (s *semaphore) acquire(need []amount) {
+	s.mu.Lock()
+	if s.fits(need) && !s.claimed(need, len(s.waiters)) {
+		s.take(need)
+		s.mu.Unlock()
+		return
+	}
+	ready := make(chan struct{})
+	s.waiters = append(s.waiters, semaphoreWaiter{need: need, ready: ready})
+	s.mu.Unlock()
+	<-ready
+}
+
+// release frees need, and hands what is free to the waiters it lets through.
+func (s *semaphore) release(need []amount) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, a := range need {
+		s.used[a.resource] -= a.n
+	}
+	waiting := s.waiters[:0]
+	for _, w := range s.waiters {
+		// Waiters still waiting come before the ones after them
+		if s.fits(w.need) && !claimedBy(waiting, w.need) {
+			s.take(w.need)
+			close(w.ready)
+		} else {
+			waiting = append(waiting, w)
+		}
+	}
+	clear(s.waiters[len(waiting):])
+	s.waiters = waiting
+}
+
+// fits reports whether need is free. s.mu must be held.
+func (s *semaphore) fits(need []amount) bool {
+	for _, a := range need {
+		if s.used[a.resource]+a.n > s.size[a.resource] {
+			return false
+		}
+	}
+	return true
+}
+
+// take marks need as used. s.mu must be held.
+func (s *semaphore) take(need []amount) {
+	for _, a := range need {
+		s.used[a.resource] += a.n
+	}
+}
+
+// claimed reports whether one of the first n waiters needs one of the resources of need. s.mu must be held.
+func (s *semaphore) claimed(need []amount, n int) bool {
+	return claimedBy(s.waiters[:n], need)
+}
+
+func claimedBy(waiters []semaphoreWaiter, need []amount) bool {
+	for _, w := range waiters {
+		for _, a := range w.need {
+			for _, b := range need {
+				if a.resource == b.resource {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// admission limits the items of a call to the capacities set with WithCapacity or WithResources. A nil
+// admission admits every item at once.
+type admission[I any] struct {
+	sem   *semaphore
+	names []string // Names of the resources, by index
+	need  func(I) ([]amount, error)
+}
+
+// newAdmission returns the admission for items of type I set in opts, or nil if there is none.
+func newAdmission[I any](opts Options) (*admission[I], error) {
+	weight, err := optionFunc[func(I) int64](opts.weight)
+	if err != nil {
+		return nil, err
+	}
+	demand, err := optionFunc[func(I) map[string]int64](opts.demand)
+	if err != nil {
+		return nil, err
+	}
+	if weight == nil && demand == nil {
+		return nil, nil
+	}
+
+	a := &admission[I]{}
+	index := make(map[string]int, len(opts.resources))
+	size := make([]int64, 0, len(opts.resources))
+	for name, capacity := range opts.resources {
+		index[name] = len(a.names)
+		a.names = append(a.names, name)
+		size = append(size, capacity)
+	}
+	a.sem = newSemaphore(size)
+
+	if weight != nil {
+		// A single unnamed resource, see WithCapacity
+		a.need = func(x I) ([]amount, error) {
+			return a.check([]amount{{resource: 0, n: max(0, weight(x))}})
+		}
+		return a, nil
+	}
+	a.need = func(x I) ([]amount, error) {
+		var need []amount
+		for name, n := range demand(x) {
+			if n <= 0 {
+				continue
+			}
+			resource, ok := index[name]
+			if !ok {
+				return nil, fmt.Errorf("%w: needs %d of %q, which has no capacity", ErrCapacityExceeded, n, name)
+			}
+			need = append(need, amount{resource: resource, n: n})
+		}
+		return a.check(need)
+	}
+	return a, nil
+}
+
+// check returns need, or ErrCapacityExceeded if it can never be acquired.
+func (a *admission[I]) check(need []amount) ([]amount, error) {
+	for _, b := range need {
+		if size := a.sem.size[b.resource]; b.n > size {
+			if name := a.names[b.resource]; name != "" {
+				return nil, fmt.Errorf("%w: needs %d of %q, capacity %d", ErrCapacityExceeded, b.n, name, size)
+			}
+			return nil, fmt.Errorf("%w: weight %d, capacity %d", ErrCapacityExceeded, b.n, size)
+		}
+	}
+	return need, nil
+}
+
+// acquire waits for the resources x needs to be free and takes them. They are returned to be released
+// once x is done.
+func (a *admission[I]) acquire(x I) ([]amount, error) {
+	if a == nil {
+		return nil, nil
+	}
+	need, err := a.need(x)
+	if err != nil {
+		return nil, err
+	}
+	a.sem.acquire(need)
+	return need, nil
+}
+
+// release frees the resources taken by acquire.
+func (a *admission[I]) release(need []amount) {
+	if a != nil {
+		a.sem.release(need)
+	}
+}
@@ -0,0 +1,206 @@
+package toil
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSemaphore_FIFO(t *testing.T) {
+	s := newSemaphore([]int64{10})
+	s.acquire([]amount{{0, 6}})
+
+	// A heavy acquire waits, and a light one that would fit waits behind it
+	heavy := make(chan struct{})
+	go func() {
+		s.acquire([]amount{{0, 8}})
+		close(heavy)
+	}()
+	for {
+		s.mu.Lock()
+		waiting := len(s.waiters)
+		s.mu.Unlock()
+		if waiting == 1 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	light := make(chan struct{})
+	go func() {
+		s.acquire([]amount{{0, 2}})
+		close(light)
+	}()
+
+	select {
+	case <-light:
+		t.Fatal("Expected the light acquire to wait behind the heavy one")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	s.release([]amount{{0, 6}})
+	<-heavy
+	<-light
+	if s.used[0] != 10 {
+		t.Errorf("Expected 10 in use, got %d", s.used[0])
+	}
+}
+
+func TestSemaphore_OtherResources(t *testing.T) {
+	s := newSemaphore([]int64{1, 1})
+	s.acquire([]amount{{0, 1}})
+
+	// Waits for resource 0, and holds no part of resource 1 meanwhile
+	both := make(chan struct{})
+	go func() {
+		s.acquire([]amount{{0, 1}, {1, 1}})
+		close(both)
+	}()
+	for {
+		s.mu.Lock()
+		waiting := len(s.waiters)
+		s.mu.Unlock()
+		if waiting == 1 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if s.used[1] != 0 {
+		t.Errorf("Expected a waiting acquire to hold nothing, resource 1 has %d in use", s.used[1])
+	}
+
+	// Resource 1 alone is claimed by the waiter, so a later acquire of it waits too
+	second := make(chan struct{})
+	go func() {
+		s.acquire([]amount{{1, 1}})
+		close(second)
+	}()
+	select {
+	case <-second:
+		t.Fatal("Expected the acquire of resource 1 to wait behind the earlier waiter")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	s.release([]amount{{0, 1}})
+	<-both
+	s.release([]amount{{0, 1}, {1, 1}})
+	<-second
+}
+
+func TestWithCapacity(t *testing.T) {
+	input := make([]int64, 200)
+	for i := range input {
+		input[i] = int64(1 + i%7)
+		if i%50 == 0 {
+			input[i] = 20
+		}
+	}
+
+	var (
+		mu      sync.Mutex
+		used    int64
+		maxUsed int64
+	)
+	process := func(w int64) (int64, error) {
+		mu.Lock()
+		used += w
+		maxUsed = max(maxUsed, used)
+		mu.Unlock()
+		time.Sleep(100 * time.Microsecond)
+		mu.Lock()
+		used -= w
+		mu.Unlock()
+		return w * 2, nil
+	}
+
+	opts := WithCapacity(Options{}.WithWorkers(8), 20, func(w int64) int64 { return w })
+	results, err := ParallelTransform(input, process, opts)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for i, result := range results {
+		if result != input[i]*2 {
+			t.Fatalf("Expected result[%d] to be %d, got %d", i, input[i]*2, result)
+		}
+	}
+	if maxUsed > 20 {
+		t.Errorf("Expected at most 20 of the capacity in use, got %d", maxUsed)
+	}
+}
+
+func TestWithCapacity_Errors(t *testing.T) {
+	identity := func(x int64) (int64, error) { return x, nil }
+
+	opts := WithCapacity(Options{}.WithWorkers(2), 10, func(w int64) int64 { return w })
+	results, err := ParallelTransform([]int64{1, 11, 3}, identity, opts)
+	if !errors.Is(err, ErrCapacityExceeded) {
+		t.Errorf("Expected ErrCapacityExceeded, got %v", err)
+	}
+	if results[0] != 1 || results[1] != 0 || results[2] != 3 {
+		t.Errorf("Expected the other items to run, got %v", results)
+	}
+
+	opts = WithCapacity(Options{}, 10, func(s string) int64 { return int64(len(s)) })
+	if _, err := ParallelTransform([]int64{1}, identity, opts); !errors.Is(err, ErrOptionType) {
+		t.Errorf("Expected ErrOptionType, got %v", err)
+	}
+}
+
+func TestWithResources(t *testing.T) {
+	type step struct {
+		cpu, db, disk int64
+	}
+	input := make([]step, 300)
+	for i := range input {
+		input[i] = step{cpu: int64(1 + i%2), db: int64(i % 3), disk: int64(i % 5 / 4)}
+	}
+	capacities := map[string]int64{"cpu": 3, "db-conns": 2, "disk-io": 1}
+	demand := func(s step) map[string]int64 {
+		return map[string]int64{"cpu": s.cpu, "db-conns": s.db, "disk-io": s.disk}
+	}
+
+	var (
+		mu      sync.Mutex
+		used    step
+		maxUsed step
+	)
+	run := func(s step) (int, error) {
+		mu.Lock()
+		used.cpu, used.db, used.disk = used.cpu+s.cpu, used.db+s.db, used.disk+s.disk
+		maxUsed.cpu, maxUsed.db, maxUsed.disk = max(maxUsed.cpu, used.cpu), max(maxUsed.db, used.db), max(maxUsed.disk, used.disk)
+		mu.Unlock()
+		time.Sleep(50 * time.Microsecond)
+		mu.Lock()
+		used.cpu, used.db, used.disk = used.cpu-s.cpu, used.db-s.db, used.disk-s.disk
+		mu.Unlock()
+		return 1, nil
+	}
+
+	results, err := ParallelTransform(input, run, WithResources(Options{}.WithWorkers(16), capacities, demand))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for i, result := range results {
+		if result != 1 {
+			t.Fatalf("Expected item %d to run", i)
+		}
+	}
+	if maxUsed.cpu > 3 || maxUsed.db > 2 || maxUsed.disk > 1 {
+		t.Errorf("Expected usage within the capacities, got %+v", maxUsed)
+	}
+}
+
+func TestWithResources_Errors(t *testing.T) {
+	capacities := map[string]int64{"cpu": 2}
+	demand := func(need map[string]int64) map[string]int64 { return need }
+	identity := func(need map[string]int64) (int, error) { return len(need), nil }
+
+	input := []map[string]int64{{"cpu": 1}, {"cpu": 3}, {"gpu": 1}, {"cpu": 1, "gpu": 0}}
+	results, err := ParallelTransform(input, identity, WithResources(Options{}.WithWorkers(2), capacities, demand))
+	if !errors.Is(err, ErrCapacityExceeded) {
+		t.Errorf("Expected ErrCapacityExceeded, got %v", err)
+	}
+	if results[0] != 1 || results[1] != 0 || results[2] != 0 || results[3] != 2 {
+		t.Errorf("Expected only the items within capacity to run, got %v", results)
+	}
+}
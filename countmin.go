@@ -0,0 +1,84 @@
+package toil
+
+import (
+	"math"
+)
+
+// CountMin is a Count-Min sketch, estimating how often each item occurs in a stream using a fixed
+// depth x width table of counters. Estimates never undercount; with width ceil(e/epsilon) and depth
+// ceil(ln(1/delta)) they overcount by more than epsilon*Total() with probability at most delta.
+// Items are added by hash, see HashString, and the sketch keeps no keys: it can check whether a given
+// item is a heavy hitter, with an estimate above a fraction of Total(), but can't list them. Finding heavy
+// hitters needs candidate keys from the caller, for example a sample of the input or a list of known keys.
+type CountMin struct {
+	width, depth int
+	counts       []uint64
+	total        uint64
+}
+
+// NewCountMin returns an empty Count-Min sketch. width and depth are at least 1.
+func NewCountMin(width, depth int) *CountMin {
+	width, depth = max(width, 1), max(depth, 1)
+	return &CountMin{width: width, depth: depth, counts: make([]uint64, width*depth)}
+}
+
+// NewCountMinWithError returns an empty Count-Min sketch sized so that estimates exceed the true count
+// by more than epsilon times the total count with probability at most delta.
+func NewCountMinWithError(epsilon, delta float64) *CountMin {
+	width := int(math.Ceil(math.E / epsilon))
+	depth := int(math.Ceil(math.Log(1 / delta)))
+	return NewCountMin(width, depth)
+}
+
+// column returns the counter used for hash in row, deriving a hash per row from the two halves of hash.
+func (c *CountMin) column(hash uint64, row int) int {
+	h1, h2 := hash&0xffffffff, hash>>32|1
+	return int((h1 + uint64(row)*h2) % uint64(c.width))
+}
+
+// AddHash adds count occurrences of an item, given its 64-bit hash, to c.
+func (c *CountMin) AddHash(hash uint64, count uint64) {
+	for row := 0; row < c.depth; row++ {
+		c.counts[row*c.width+c.column(hash, row)] += count
+	}
+	c.total += count
+}
+
+// EstimateHash returns the estimated number of occurrences of an item, given its 64-bit hash.
+func (c *CountMin) EstimateHash(hash uint64) uint64 {
+	estimate := uint64(math.MaxUint64)
+	for row := 0; row < c.depth; row++ {
+		estimate = min(estimate, c.counts[row*c.width+c.column(hash, row)])
+	}
+	return estimate
+}
+
+// Total returns the total number of occurrences added to c.
+func (c *CountMin) Total() uint64 {
+	return c.total
+}
+
+// Merge returns a new sketch counting the items of both c and o, which must have the same dimensions.
+// Method expression (*CountMin).Merge is a ReduceFunc.
+func (c *CountMin) Merge(o *CountMin) (*CountMin, error) {
+	if c.width != o.width || c.depth != o.depth {
+		return nil, ErrSketchMismatch
+	}
+	merged := &CountMin{width: c.width, depth: c.depth, counts: make([]uint64, len(c.counts)), total: c.total + o.total}
+	for i := range merged.counts {
+		merged.counts[i] = c.counts[i] + o.counts[i]
+	}
+	return merged, nil
+}
+
+// CountMinOf is an Aggregator counting occurrences of items, as hashed by hash.
+func CountMinOf[T any](width, depth int, hash func(T) uint64) Aggregator[T, *CountMin] {
+	return Aggregator[T, *CountMin]{
+		Zero: func() *CountMin { return NewCountMin(width, depth) },
+		Add: func(acc *CountMin, _ int, x T) (*CountMin, error) {
+			acc.AddHash(hash(x), 1)
+			return acc, nil
+		},
+		Merge: (*CountMin).Merge,
+	}
+}
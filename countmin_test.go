@@ -0,0 +1,54 @@
+package toil
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func TestCountMin_Estimate(t *testing.T) {
+	c := NewCountMinWithError(0.001, 0.01)
+	for i := 0; i < 10000; i++ {
+		c.AddHash(HashUint64(uint64(i%1000)), 1)
+	}
+	c.AddHash(HashString("heavy"), 5000)
+
+	if c.Total() != 15000 {
+		t.Errorf("Expected total 15000, got %d", c.Total())
+	}
+	if estimate := c.EstimateHash(HashString("heavy")); estimate < 5000 || estimate > 5000+15 {
+		t.Errorf("Expected heavy hitter estimate close to 5000, got %d", estimate)
+	}
+	if estimate := c.EstimateHash(HashUint64(7)); estimate < 10 {
+		t.Errorf("Count-Min must never undercount, got %d for 10 occurrences", estimate)
+	}
+}
+
+func TestCountMin_ParallelAggregate(t *testing.T) {
+	input := make([]string, 100000)
+	for i := range input {
+		if i%10 == 0 {
+			input[i] = "popular"
+		} else {
+			input[i] = "item-" + strconv.Itoa(i)
+		}
+	}
+
+	c, err := ParallelAggregate(input, CountMinOf(2048, 5, HashString), Options{}.WithWorkers(4))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if c.Total() != uint64(len(input)) {
+		t.Errorf("Expected total %d, got %d", len(input), c.Total())
+	}
+	if estimate := c.EstimateHash(HashString("popular")); estimate < 10000 || estimate > 10500 {
+		t.Errorf("Expected about 10000 occurrences of popular, got %d", estimate)
+	}
+}
+
+func TestCountMin_MergeMismatch(t *testing.T) {
+	_, err := NewCountMin(100, 4).Merge(NewCountMin(100, 5))
+	if !errors.Is(err, ErrSketchMismatch) {
+		t.Fatalf("Expected ErrSketchMismatch, got %v", err)
+	}
+}
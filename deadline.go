@@ -0,0 +1,43 @@
+package toil
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrDeadlineMissed is the error of items skipped because they missed their deadline, see SkipMissedDeadlines.
+var ErrDeadlineMissed = errors.New("toil: deadline missed")
+
+// DeadlineError reports the items skipped because their deadline had passed by the time a worker was free
+// to start them. It matches ErrDeadlineMissed with errors.Is.
+type DeadlineError struct {
+	Missed []int // Indices of the items skipped, in increasing order
+}
+
+func (e *DeadlineError) Error() string {
+	if len(e.Missed) == 1 {
+		return fmt.Sprintf("%v: item %d", ErrDeadlineMissed, e.Missed[0])
+	}
+	return fmt.Sprintf("%v: %d items", ErrDeadlineMissed, len(e.Missed))
+}
+
+func (e *DeadlineError) Unwrap() error {
+	return ErrDeadlineMissed
+}
+
+// joinMissed joins err with a *DeadlineError for the items i with missed[i] set, if any.
+func joinMissed(err error, missed []bool) error {
+	var deadlineErr DeadlineError
+	for i, m := range missed {
+		if m {
+			deadlineErr.Missed = append(deadlineErr.Missed, i)
+		}
+	}
+	switch {
+	case deadlineErr.Missed == nil:
+		return err
+	case err == nil:
+		return &deadlineErr
+	}
+	return errors.Join(err, &deadlineErr)
+}
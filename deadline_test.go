@@ -0,0 +1,132 @@
+package toil
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+type notification struct {
+	id  int
+	due time.Time
+}
+
+func notificationDeadline(n notification) time.Time {
+	return n.due
+}
+
+func TestWithDeadline_Order(t *testing.T) {
+	now := time.Now()
+	input := []notification{
+		{0, now.Add(3 * time.Hour)},
+		{1, time.Time{}},
+		{2, now.Add(time.Hour)},
+		{3, now.Add(2 * time.Hour)},
+	}
+	var started []int
+	send := func(n notification) (int, error) {
+		started = append(started, n.id)
+		return n.id, nil
+	}
+
+	results, err := ParallelTransform(input, send, WithDeadline(Options{}.WithWorkers(1), notificationDeadline))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !slices.Equal(results, []int{0, 1, 2, 3}) {
+		t.Errorf("Expected results in input order, got %v", results)
+	}
+	// Earliest deadline first, no deadline last
+	if expected := []int{2, 3, 0, 1}; !slices.Equal(started, expected) {
+		t.Errorf("Expected items to start in order %v, got %v", expected, started)
+	}
+}
+
+func TestWithDeadline_TieBreak(t *testing.T) {
+	due := time.Now().Add(time.Hour)
+	input := []notification{{0, due}, {1, due}, {2, due.Add(-time.Minute)}}
+	var started []int
+	send := func(n notification) (int, error) {
+		started = append(started, n.id)
+		return n.id, nil
+	}
+
+	opts := WithDeadline(Options{}.WithWorkers(1), notificationDeadline)
+	opts = WithPriority(opts, func(n notification) int { return n.id })
+	if _, err := ParallelTransform(input, send, opts); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// Equal deadlines go by priority
+	if expected := []int{2, 1, 0}; !slices.Equal(started, expected) {
+		t.Errorf("Expected items to start in order %v, got %v", expected, started)
+	}
+}
+
+func TestSkipMissedDeadlines(t *testing.T) {
+	now := time.Now()
+	input := []notification{
+		{0, now.Add(-time.Minute)},
+		{1, now.Add(time.Hour)},
+		{2, now.Add(20 * time.Millisecond)},
+		{3, now.Add(-time.Second)},
+	}
+	var sent []int
+	send := func(n notification) (int, error) {
+		sent = append(sent, n.id)
+		if n.id == 3 || n.id == 0 {
+			t.Errorf("Item %d missed its deadline but was sent", n.id)
+		}
+		if n.id == 2 {
+			// Makes item 1 late by the time it starts
+			time.Sleep(30 * time.Millisecond)
+		}
+		return n.id + 10, nil
+	}
+
+	// Without skipping, late items still run
+	opts := WithDeadline(Options{}.WithWorkers(1), notificationDeadline)
+	results, err := ParallelTransform(input[1:3], func(n notification) (int, error) { return n.id, nil }, opts)
+	if err != nil || !slices.Equal(results, []int{1, 2}) {
+		t.Fatalf("Expected every item to run, got %v and %v", results, err)
+	}
+
+	input[1].due = now.Add(25 * time.Millisecond)
+	results, err = ParallelTransform(input, send, opts.SkipMissedDeadlines(true).StopOnError(true))
+	if !errors.Is(err, ErrDeadlineMissed) {
+		t.Fatalf("Expected ErrDeadlineMissed, got %v", err)
+	}
+	var deadlineErr *DeadlineError
+	if !errors.As(err, &deadlineErr) || !slices.Equal(deadlineErr.Missed, []int{0, 1, 3}) {
+		t.Errorf("Expected items 0, 1 and 3 to miss their deadline, got %v", err)
+	}
+	// Missed deadlines don't fail the call
+	if !slices.Equal(results, []int{0, 0, 12, 0}) {
+		t.Errorf("Expected results for the items sent only, got %v", results)
+	}
+	if !slices.Equal(sent, []int{2}) {
+		t.Errorf("Expected only item 2 to be sent, got %v", sent)
+	}
+}
+
+func TestSkipMissedDeadlines_WithError(t *testing.T) {
+	input := []notification{{0, time.Now().Add(-time.Minute)}, {1, time.Now().Add(time.Hour)}}
+	errSend := errors.New("send failed")
+	fail := func(notification) (int, error) {
+		return 0, errSend
+	}
+
+	opts := WithDeadline(Options{}.WithWorkers(2), notificationDeadline).SkipMissedDeadlines(true)
+	results, err := ParallelTransform(input, fail, opts)
+	if !errors.Is(err, errSend) || !errors.Is(err, ErrDeadlineMissed) {
+		t.Errorf("Expected both errors, got %v", err)
+	}
+	if results == nil {
+		t.Error("Expected results without StopOnError")
+	}
+
+	results, err = ParallelTransform(input, fail, opts.StopOnError(true))
+	if !errors.Is(err, errSend) || results != nil {
+		t.Errorf("Expected nil results and the error, got %v and %v", results, err)
+	}
+}
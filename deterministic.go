@@ -0,0 +1,106 @@
+package toil
+
+import (
+	"math"
+	"runtime"
+)
+
+// deterministicBlockSize is the number of consecutive items folded sequentially before block
+// results are combined pairwise in deterministic mode. It must never depend on the worker count.
+const deterministicBlockSize = 256
+
+// deterministicReduce folds fixed-size blocks of v from left to right and then combines the block
+// results with reduceTree. Neither step depends on opts.workers, so the result is reproducible.
+func deterministicReduce[T any](v []T, f ReduceFunc[T], absorbing func(T) bool, opts Options) (T, error) {
+	var zero T
+	partials, absorbed, err := foldBlocks(v, deterministicBlockSize, opts, func(lo int, block []T) (T, bool, error) {
+		acc := block[0]
+		if absorbing != nil && absorbing(acc) {
+			return acc, true, nil
+		}
+		for i, x := range block[1:] {
+			if absorbing != nil && absorbing(x) {
+				return x, true, nil
+			}
+			var err error
+			if acc, err = f(acc, x); err != nil {
+				at := lo + 1 + i
+				return acc, false, &ReduceError{
+					Level:    0,
+					Operands: [][]Range{{{Lo: lo, Hi: at}}, {{Lo: at, Hi: at + 1}}},
+					Err:      err,
+				}
+			}
+			if absorbing != nil && absorbing(acc) {
+				return acc, true, nil
+			}
+		}
+		return acc, false, nil
+	})
+	if err != nil {
+		return zero, err
+	}
+	if absorbed != nil {
+		return *absorbed, nil
+	}
+	return reduceTree(partials, f, absorbing, treeLayout{size: deterministicBlockSize, total: len(v), level: 1}, opts)
+}
+
+// CompensatedSum is a float64 running total that tracks the rounding error lost by each addition
+// (Kahan-Babuska-Neumaier summation), so long sums of values with very different magnitudes stay accurate.
+// The zero value is an empty sum.
+type CompensatedSum struct {
+	Sum          float64
+	Compensation float64
+}
+
+// Add returns s with x added to it.
+func (s CompensatedSum) Add(x float64) CompensatedSum {
+	t := s.Sum + x
+	if math.Abs(s.Sum) >= math.Abs(x) {
+		s.Compensation += (s.Sum - t) + x
+	} else {
+		s.Compensation += (x - t) + s.Sum
+	}
+	s.Sum = t
+	return s
+}
+
+// Value returns the compensated total.
+func (s CompensatedSum) Value() float64 {
+	return s.Sum + s.Compensation
+}
+
+// MergeCompensated is a ReduceFunc combining two compensated partial sums.
+func MergeCompensated(a, b CompensatedSum) (CompensatedSum, error) {
+	a = a.Add(b.Sum)
+	a.Compensation += b.Compensation
+	return a, nil
+}
+
+// SumFloat64 sums v in parallel using compensated summation. The sum is always computed
+// deterministically: the result is bit-identical for a given input whatever the number of workers.
+func SumFloat64(v []float64, opts Options) (float64, error) {
+	if len(v) == 0 {
+		return 0, nil
+	}
+	if opts.workers <= 0 {
+		opts.workers = runtime.NumCPU()
+	}
+
+	partials, _, err := foldBlocks(v, deterministicBlockSize, opts, func(_ int, block []float64) (CompensatedSum, bool, error) {
+		var s CompensatedSum
+		for _, x := range block {
+			s = s.Add(x)
+		}
+		return s, false, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	total, err := reduceTree(partials, MergeCompensated, nil, treeLayout{size: deterministicBlockSize, total: len(v), level: 1}, opts)
+	if err != nil {
+		return 0, err
+	}
+	return total.Value(), nil
+}
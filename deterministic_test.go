@@ -0,0 +1,127 @@
+package toil
+
+import (
+	"errors"
+	"math"
+	"runtime"
+	"testing"
+)
+
+func floatInput(size int) []float64 {
+	input := make([]float64, size)
+	for i := range input {
+		input[i] = math.Sin(float64(i)) * math.Pow(10, float64(i%17-8))
+	}
+	return input
+}
+
+func TestParallelReduce_DeterministicAcrossWorkers(t *testing.T) {
+	input := floatInput(100003)
+	sumFunc := func(a, b float64) (float64, error) { return a + b, nil }
+
+	expected, err := ParallelReduce(input, sumFunc, Options{}.WithWorkers(1).Deterministic(true))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, workers := range []int{2, 3, 7, runtime.NumCPU(), 64} {
+		result, err := ParallelReduce(input, sumFunc, Options{}.WithWorkers(workers).Deterministic(true))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if math.Float64bits(result) != math.Float64bits(expected) {
+			t.Errorf("Workers=%d: expected %v, got %v", workers, expected, result)
+		}
+	}
+}
+
+func TestParallelReduce_DeterministicOrder(t *testing.T) {
+	input := make([]string, 1000)
+	for i := range input {
+		input[i] = string(rune('a' + i%26))
+	}
+	concat := func(a, b string) (string, error) { return a + b, nil }
+
+	expected := ""
+	for _, s := range input {
+		expected += s
+	}
+
+	result, err := ParallelReduce(input, concat, Options{}.WithWorkers(4).Deterministic(true))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result != expected {
+		t.Errorf("Expected deterministic reduction to preserve order")
+	}
+}
+
+func TestParallelReduce_DeterministicError(t *testing.T) {
+	input := make([]int, 1000)
+	errFunc := func(a, b int) (int, error) { return 0, errors.New("fail") }
+	_, err := ParallelReduce(input, errFunc, Options{}.WithWorkers(4).Deterministic(true))
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+}
+
+func TestParallelReduce_DeterministicAbsorbing(t *testing.T) {
+	input := make([]int, 10000)
+	for i := range input {
+		input[i] = 1
+	}
+	input[7777] = 0
+	prodFunc := func(a, b int) (int, error) { return a * b, nil }
+	opts := WithAbsorbing(Options{}.WithWorkers(4).Deterministic(true), func(x int) bool { return x == 0 })
+	result, err := ParallelReduce(input, prodFunc, opts)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result != 0 {
+		t.Errorf("Expected 0, got %d", result)
+	}
+}
+
+func TestCompensatedSum(t *testing.T) {
+	var s CompensatedSum
+	s = s.Add(1e100).Add(1.0).Add(-1e100)
+	if s.Value() != 1.0 {
+		t.Errorf("Expected compensated sum 1, got %v", s.Value())
+	}
+}
+
+func TestSumFloat64(t *testing.T) {
+	input := make([]float64, 10001)
+	input[0] = 1e16
+	for i := 1; i < len(input); i++ {
+		input[i] = 1.0
+	}
+
+	result, err := SumFloat64(input, Options{}.WithWorkers(4))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result != 1e16+10000 {
+		t.Errorf("Expected %v, got %v", 1e16+10000, result)
+	}
+
+	for _, workers := range []int{1, 3, 16} {
+		other, err := SumFloat64(input, Options{}.WithWorkers(workers))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if math.Float64bits(other) != math.Float64bits(result) {
+			t.Errorf("Workers=%d: expected %v, got %v", workers, result, other)
+		}
+	}
+}
+
+func TestSumFloat64_Empty(t *testing.T) {
+	result, err := SumFloat64(nil, Options{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result != 0 {
+		t.Errorf("Expected 0 for empty input, got %v", result)
+	}
+}
@@ -0,0 +1,52 @@
+package toil
+
+// ParallelDistinct returns the items of v without duplicates, keeping the first occurrence of every item
+// in its original order. Each block of v first records where its items first occur, split by shard of a
+// ShardedMap; every shard then keeps the earliest occurrence across blocks, without locking.
+func ParallelDistinct[T comparable](v []T, opts Options) []T {
+	opts = opts.resolve(len(v))
+	firsts := NewShardedMap[T, int](opts.workers)
+	shards := len(firsts.shards)
+	s := opts.split(len(v))
+
+	// First pass: the first index of every item within each block, per shard.
+	local := make([][]map[T]int, s.count())
+	_ = forEachBlock(s, opts, func(_ int, b block) error {
+		local[b.index] = make([]map[T]int, shards)
+		for i := b.lo; i < b.hi; i++ {
+			shard := firsts.shardOf(v[i])
+			if local[b.index][shard] == nil {
+				local[b.index][shard] = make(map[T]int)
+			}
+			if _, ok := local[b.index][shard][v[i]]; !ok {
+				local[b.index][shard][v[i]] = i
+			}
+		}
+		return nil
+	})
+
+	// Second pass: every shard keeps the first index across blocks, which come in index order.
+	_ = forEachBlock(fixedSplit(shards, 1), opts, func(_ int, b block) error {
+		out := firsts.shards[b.index].m
+		for _, blockShards := range local {
+			for x, i := range blockShards[b.index] {
+				if _, ok := out[x]; !ok {
+					out[x] = i
+				}
+			}
+		}
+		return nil
+	})
+
+	indices := make([]int, 0, firsts.Len())
+	for _, i := range firsts.All() {
+		indices = append(indices, i)
+	}
+	ParallelSort(indices, opts)
+
+	out := make([]T, len(indices))
+	for j, i := range indices {
+		out[j] = v[i]
+	}
+	return out
+}
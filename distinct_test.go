@@ -0,0 +1,34 @@
+package toil
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParallelDistinct(t *testing.T) {
+	input := make([]int, 10000)
+	for i := range input {
+		input[i] = (i * 7) % 101
+	}
+	var expected []int
+	seen := map[int]bool{}
+	for _, x := range input {
+		if !seen[x] {
+			seen[x] = true
+			expected = append(expected, x)
+		}
+	}
+
+	for _, workers := range []int{1, 3, 8} {
+		result := ParallelDistinct(input, Options{}.WithWorkers(workers))
+		if !slices.Equal(result, expected) {
+			t.Errorf("Workers=%d: expected first occurrences in order, got %v", workers, result)
+		}
+	}
+}
+
+func TestParallelDistinct_Empty(t *testing.T) {
+	if result := ParallelDistinct([]string{}, Options{}); len(result) != 0 {
+		t.Errorf("Expected no items, got %v", result)
+	}
+}
@@ -0,0 +1,82 @@
+package toil
+
+// PredicateFunc reports whether an item matches. If it returns an error, the item is treated as
+// not matching and the error is handled like a TransformFunc error.
+type PredicateFunc[T any] func(T) (bool, error)
+
+// FlatMapFunc transforms an item into any number of output items.
+type FlatMapFunc[I any, O any] func(I) ([]O, error)
+
+// ParallelFilter returns the items of v matching keep, in their original order. The predicate is evaluated
+// in parallel over contiguous blocks of v, and only matching items are ever copied.
+// Errors are handled as in ParallelTransform: if opts has StopOnError set, the first error stops processing
+// and nil is returned with it; otherwise every item is tested, failed items are left out, and the matching
+// items are returned along with the first error.
+func ParallelFilter[T any](v []T, keep PredicateFunc[T], opts Options) ([]T, error) {
+	opts = opts.resolve(len(v))
+	s := opts.split(len(v))
+	parts := make([][]T, s.count())
+
+	err := forEachItem(s, opts, func(_ int, b block, i int) error {
+		ok, err := keep(v[i])
+		if err != nil {
+			return err
+		}
+		if ok {
+			parts[b.index] = append(parts[b.index], v[i])
+		}
+		return nil
+	})
+	if err != nil && opts.stopOnError {
+		return nil, err
+	}
+	return concat(parts), err
+}
+
+// ParallelFlatMap applies f to every item of v in parallel and concatenates the results in input order.
+// Errors are handled as in ParallelFilter; the output of failed items is left out.
+func ParallelFlatMap[I any, O any](v []I, f FlatMapFunc[I, O], opts Options) ([]O, error) {
+	opts = opts.resolve(len(v))
+	s := opts.split(len(v))
+	parts := make([][]O, s.count())
+
+	err := forEachItem(s, opts, func(_ int, b block, i int) error {
+		out, err := f(v[i])
+		if err != nil {
+			return err
+		}
+		parts[b.index] = append(parts[b.index], out...)
+		return nil
+	})
+	if err != nil && opts.stopOnError {
+		return nil, err
+	}
+	return concat(parts), err
+}
+
+// ParallelPartition splits v into the items matching pred and the items not matching it, both in their
+// original order. Errors are handled as in ParallelFilter; failed items are in neither slice.
+func ParallelPartition[T any](v []T, pred PredicateFunc[T], opts Options) (matched, unmatched []T, err error) {
+	opts = opts.resolve(len(v))
+	s := opts.split(len(v))
+	blocks := s.count()
+	matchedParts := make([][]T, blocks)
+	unmatchedParts := make([][]T, blocks)
+
+	err = forEachItem(s, opts, func(_ int, b block, i int) error {
+		ok, err := pred(v[i])
+		if err != nil {
+			return err
+		}
+		if ok {
+			matchedParts[b.index] = append(matchedParts[b.index], v[i])
+		} else {
+			unmatchedParts[b.index] = append(unmatchedParts[b.index], v[i])
+		}
+		return nil
+	})
+	if err != nil && opts.stopOnError {
+		return nil, nil, err
+	}
+	return concat(matchedParts), concat(unmatchedParts), err
+}
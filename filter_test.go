@@ -0,0 +1,111 @@
+package toil
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func isEven(x int) (bool, error) {
+	return x%2 == 0, nil
+}
+
+func TestParallelFilter(t *testing.T) {
+	input := make([]int, 1001)
+	var expected []int
+	for i := range input {
+		input[i] = i
+		if i%2 == 0 {
+			expected = append(expected, i)
+		}
+	}
+
+	result, err := ParallelFilter(input, isEven, Options{}.WithWorkers(4))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !slices.Equal(result, expected) {
+		t.Errorf("Expected even numbers in order, got %v", result)
+	}
+}
+
+func TestParallelFilter_Empty(t *testing.T) {
+	result, err := ParallelFilter([]int{}, isEven, Options{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(result) != 0 {
+		t.Errorf("Expected no results for empty input, got %v", result)
+	}
+}
+
+func TestParallelFilter_ContinueOnError(t *testing.T) {
+	input := []int{1, 2, 3, 4, 5, 6}
+	failOnFour := func(x int) (bool, error) {
+		if x == 4 {
+			return false, errors.New("fail on 4")
+		}
+		return x%2 == 0, nil
+	}
+
+	result, err := ParallelFilter(input, failOnFour, Options{}.WithWorkers(2))
+	if err == nil {
+		t.Fatal("Expected error but got none")
+	}
+	if !slices.Equal(result, []int{2, 6}) {
+		t.Errorf("Expected [2 6], got %v", result)
+	}
+}
+
+func TestParallelFilter_StopOnError(t *testing.T) {
+	input := []int{1, 2, 3, 4, 5, 6}
+	fail := func(x int) (bool, error) { return false, errors.New("fail") }
+
+	result, err := ParallelFilter(input, fail, Options{}.WithWorkers(2).StopOnError(true))
+	if err == nil {
+		t.Fatal("Expected error but got none")
+	}
+	if result != nil {
+		t.Errorf("Expected nil results when stopping on error, got %v", result)
+	}
+}
+
+func TestParallelFlatMap(t *testing.T) {
+	input := []int{0, 1, 2, 3, 4}
+	repeat := func(x int) ([]int, error) {
+		out := make([]int, x)
+		for i := range out {
+			out[i] = x
+		}
+		return out, nil
+	}
+
+	result, err := ParallelFlatMap(input, repeat, Options{}.WithWorkers(3))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []int{1, 2, 2, 3, 3, 3, 4, 4, 4, 4}
+	if !slices.Equal(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestParallelPartition(t *testing.T) {
+	input := make([]int, 100)
+	for i := range input {
+		input[i] = i
+	}
+
+	even, odd, err := ParallelPartition(input, isEven, Options{}.WithWorkers(4))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(even) != 50 || len(odd) != 50 {
+		t.Fatalf("Expected 50 even and 50 odd items, got %d and %d", len(even), len(odd))
+	}
+	for i := range 50 {
+		if even[i] != 2*i || odd[i] != 2*i+1 {
+			t.Fatalf("Expected partitions in input order, got %v and %v", even, odd)
+		}
+	}
+}
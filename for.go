@@ -0,0 +1,60 @@
+package toil
+
+import (
+	"sync/atomic"
+)
+
+// defaultTileSize is the edge length of the tiles of ParallelFor2D when none is given.
+const defaultTileSize = 64
+
+// ParallelFor calls body for every index in [0, n) in parallel, like ParallelTransform over a slice of
+// indices, but without allocating an input or an output slice. Contiguous ranges of indices are handed
+// to workers. Errors are handled as in ParallelTransform: if opts has StopOnError set, the first error stops
+// processing; otherwise every index is visited. Either way, the first error is returned.
+func ParallelFor(n int, body func(i int) error, opts Options) error {
+	opts = opts.resolve(n)
+	return forEachItem(opts.split(n), opts, func(_ int, _ block, i int) error {
+		return body(i)
+	})
+}
+
+// ParallelFor2D calls body for every cell (r, c) of a rows x cols grid in parallel. The grid is split into
+// tile x tile squares, each visited row by row by a single worker, which keeps neighbouring cells on the
+// same worker for cache locality. If tile is 0 or negative, a default of 64 is used. Errors are handled as
+// in ParallelFor.
+func ParallelFor2D(rows, cols, tile int, body func(r, c int) error, opts Options) error {
+	if rows <= 0 || cols <= 0 {
+		return nil
+	}
+	if tile <= 0 {
+		tile = defaultTileSize
+	}
+	opts = opts.resolve(rows * cols)
+	tileCols := (cols + tile - 1) / tile
+	tiles := ((rows + tile - 1) / tile) * tileCols
+
+	var failed atomic.Bool
+	return forEachBlock(fixedSplit(tiles, 1), opts, func(_ int, b block) error {
+		r0 := (b.index / tileCols) * tile
+		c0 := (b.index % tileCols) * tile
+
+		var tileErr error
+		for r := r0; r < min(r0+tile, rows); r++ {
+			for c := c0; c < min(c0+tile, cols); c++ {
+				if opts.stopOnError && failed.Load() {
+					return tileErr
+				}
+				if err := body(r, c); err != nil {
+					if opts.stopOnError {
+						failed.Store(true)
+						return err
+					}
+					if tileErr == nil {
+						tileErr = err
+					}
+				}
+			}
+		}
+		return tileErr
+	})
+}
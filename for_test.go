@@ -0,0 +1,103 @@
+package toil
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+func TestParallelFor(t *testing.T) {
+	for _, n := range []int{0, 1, 10, 10000} {
+		seen := make([]atomic.Int32, n)
+		err := ParallelFor(n, func(i int) error {
+			seen[i].Add(1)
+			return nil
+		}, Options{}.WithWorkers(4))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		for i := range seen {
+			if seen[i].Load() != 1 {
+				t.Fatalf("n=%d: expected index %d to be visited once, got %d", n, i, seen[i].Load())
+			}
+		}
+	}
+}
+
+func TestParallelFor_Errors(t *testing.T) {
+	var calls atomic.Int32
+	failOnFive := func(i int) error {
+		calls.Add(1)
+		if i == 5 {
+			return errors.New("fail on 5")
+		}
+		return nil
+	}
+
+	err := ParallelFor(100, failOnFive, Options{}.WithWorkers(2))
+	if err == nil {
+		t.Fatal("Expected error but got none")
+	}
+	if calls.Load() != 100 {
+		t.Errorf("Expected every index to be visited without StopOnError, got %d", calls.Load())
+	}
+
+	calls.Store(0)
+	err = ParallelFor(100, failOnFive, Options{}.WithWorkers(1).StopOnError(true))
+	if err == nil {
+		t.Fatal("Expected error but got none")
+	}
+	if calls.Load() != 6 {
+		t.Errorf("Expected processing to stop at the failure, got %d calls", calls.Load())
+	}
+}
+
+func TestParallelFor2D(t *testing.T) {
+	rows, cols := 130, 70
+	grid := make([][]atomic.Int32, rows)
+	for r := range grid {
+		grid[r] = make([]atomic.Int32, cols)
+	}
+
+	for _, tile := range []int{0, 1, 16, 200} {
+		err := ParallelFor2D(rows, cols, tile, func(r, c int) error {
+			grid[r][c].Add(1)
+			return nil
+		}, Options{}.WithWorkers(4))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	for r := range grid {
+		for c := range grid[r] {
+			if grid[r][c].Load() != 4 {
+				t.Fatalf("Expected cell (%d, %d) to be visited once per call, got %d", r, c, grid[r][c].Load())
+			}
+		}
+	}
+}
+
+func TestParallelFor2D_StopOnError(t *testing.T) {
+	var calls atomic.Int32
+	err := ParallelFor2D(100, 100, 10, func(r, c int) error {
+		calls.Add(1)
+		return errors.New("fail")
+	}, Options{}.WithWorkers(1).StopOnError(true))
+	if err == nil {
+		t.Fatal("Expected error but got none")
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Expected processing to stop at the first failure, got %d calls", calls.Load())
+	}
+}
+
+func BenchmarkParallelFor(b *testing.B) {
+	out := make([]int, 100000)
+	opts := Options{}
+	for b.Loop() {
+		_ = ParallelFor(len(out), func(i int) error {
+			out[i] = i * i
+			return nil
+		}, opts)
+	}
+}
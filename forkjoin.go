@@ -0,0 +1,226 @@
+package toil
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// Worker is one of the goroutines running a ForkJoin call. Tasks are given the worker running them,
+// which they spawn and join their own subtasks on.
+type Worker struct {
+	pool *forkJoinPool
+	id   int
+}
+
+// ID returns the number of the worker, from 0 to the number of workers - 1, so that tasks can keep
+// per-worker state without locking.
+func (w *Worker) ID() int {
+	return w.id
+}
+
+// Task is a computation started with Spawn, whose result is collected with Join.
+type Task[T any] struct {
+	fn     func(*Worker) (T, error)
+	done   chan struct{} // Closed once result and err are set
+	result T
+	err    error
+}
+
+// ForkJoin runs root on a pool of work-stealing workers and returns its result. root, and every task it
+// spawns in turn, can Spawn subtasks and Join them, which expresses recursive divide-and-conquer like
+// quicksort, evaluating trees or walking directories, with a single pool of opts.workers goroutines
+// however deep the recursion goes.
+// Every worker keeps a deque of the tasks it spawned and runs the newest first, while idle workers steal
+// the oldest, which are usually the largest. A task waiting in Join runs other tasks meanwhile instead of
+// blocking its worker, so joins never deadlock, even with a single worker.
+// ForkJoin returns once root and every task spawned have finished. The error of a task is returned by its
+// Join; if opts has StopOnError set, tasks not started yet when one fails are skipped, and their Join
+// returns that error.
+func ForkJoin[T any](opts Options, root func(*Worker) (T, error)) (T, error) {
+	if opts.workers <= 0 {
+		opts.workers = runtime.NumCPU()
+	}
+	p := &forkJoinPool{
+		deques:      make([]taskDeque, opts.workers),
+		wake:        make(chan struct{}, opts.workers),
+		quit:        make(chan struct{}),
+		stopOnError: opts.stopOnError,
+	}
+	task := newTask(root)
+	p.pending.Add(1)
+	p.deques[0].push(task)
+
+	var wg sync.WaitGroup
+	for id := range opts.workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.work(&Worker{pool: p, id: id})
+		}()
+	}
+	wg.Wait()
+
+	return task.result, task.err
+}
+
+// Spawn starts fn as a subtask of the task running on w, and returns it to be joined.
+// fn runs on w later, unless another worker steals it first.
+func Spawn[T any](w *Worker, fn func(*Worker) (T, error)) *Task[T] {
+	task := newTask(fn)
+	w.pool.pending.Add(1)
+	w.pool.deques[w.id].push(task)
+	select {
+	case w.pool.wake <- struct{}{}:
+	default: // Enough workers are being woken up already
+	}
+	return task
+}
+
+// Join waits for t to finish and returns its result. Meanwhile w runs other tasks, starting with
+// the ones it spawned last, which is t itself if no other worker stole it. w must be the worker running
+// the calling task.
+func (t *Task[T]) Join(w *Worker) (T, error) {
+	for !t.finished() {
+		if task, ok := w.pool.find(w.id); ok {
+			task.run(w)
+			continue
+		}
+		select {
+		case <-t.done:
+		case <-w.pool.wake:
+		}
+	}
+	return t.result, t.err
+}
+
+func newTask[T any](fn func(*Worker) (T, error)) *Task[T] {
+	return &Task[T]{fn: fn, done: make(chan struct{})}
+}
+
+func (t *Task[T]) finished() bool {
+	select {
+	case <-t.done:
+		return true
+	default:
+		return false
+	}
+}
+
+func (t *Task[T]) run(w *Worker) {
+	p := w.pool
+	if errPtr := p.failed.Load(); errPtr != nil && p.stopOnError {
+		t.err = *errPtr
+	} else {
+		t.result, t.err = t.fn(w)
+		if t.err != nil {
+			p.failed.CompareAndSwap(nil, &t.err)
+		}
+	}
+	close(t.done)
+	if p.pending.Add(-1) == 0 {
+		close(p.quit)
+	}
+}
+
+// forkJoinTask is a Task of any result type.
+type forkJoinTask interface {
+	run(w *Worker)
+}
+
+// forkJoinPool is the state shared by the workers of a ForkJoin call.
+type forkJoinPool struct {
+	deques      []taskDeque
+	wake        chan struct{} // Signalled when a task is spawned, for idle workers to look for it
+	quit        chan struct{} // Closed once every task has finished
+	pending     atomic.Int64  // Number of tasks spawned and not finished
+	failed      atomic.Pointer[error]
+	stopOnError bool
+}
+
+// work runs tasks on the worker w until every task has finished.
+func (p *forkJoinPool) work(w *Worker) {
+	for {
+		if task, ok := p.find(w.id); ok {
+			task.run(w)
+			continue
+		}
+		select {
+		case <-p.wake:
+		case <-p.quit:
+			return
+		}
+	}
+}
+
+// find takes the newest task of worker self, or else steals the oldest task of another worker.
+func (p *forkJoinPool) find(self int) (forkJoinTask, bool) {
+	if task, ok := p.deques[self].pop(); ok {
+		return task, true
+	}
+	for i := 1; i < len(p.deques); i++ {
+		if task, ok := p.deques[(self+i)%len(p.deques)].steal(); ok {
+			return task, true
+		}
+	}
+	return nil, false
+}
+
+// taskDeque holds the tasks spawned by a worker and not started yet. The owner pushes and pops at the
+// bottom, running the task it spawned last, while it is still in cache; thieves steal from the top,
+// taking the oldest task, which in divide-and-conquer is the largest.
+type taskDeque struct {
+	mu    sync.Mutex
+	tasks []forkJoinTask
+	top   int          // Index of the oldest task in tasks
+	size  atomic.Int64 // Number of tasks, so that thieves can skip empty deques without locking them
+}
+
+func (d *taskDeque) push(task forkJoinTask) {
+	d.mu.Lock()
+	d.tasks = append(d.tasks, task)
+	d.size.Add(1)
+	d.mu.Unlock()
+}
+
+func (d *taskDeque) pop() (forkJoinTask, bool) {
+	if d.size.Load() == 0 {
+		return nil, false
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if len(d.tasks) == d.top {
+		return nil, false
+	}
+	task := d.tasks[len(d.tasks)-1]
+	d.tasks[len(d.tasks)-1] = nil
+	d.tasks = d.tasks[:len(d.tasks)-1]
+	d.removed()
+	return task, true
+}
+
+func (d *taskDeque) steal() (forkJoinTask, bool) {
+	if d.size.Load() == 0 {
+		return nil, false
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if len(d.tasks) == d.top {
+		return nil, false
+	}
+	task := d.tasks[d.top]
+	d.tasks[d.top] = nil
+	d.top++
+	d.removed()
+	return task, true
+}
+
+// removed records that a task was taken from d, and reuses its storage from the start once it is empty.
+// d.mu must be held.
+func (d *taskDeque) removed() {
+	d.size.Add(-1)
+	if len(d.tasks) == d.top {
+		d.tasks = d.tasks[:0]
+		d.top = 0
+	}
+}
@@ -0,0 +1,190 @@
+package toil
+
+import (
+	"errors"
+	"runtime"
+	"slices"
+	"sync/atomic"
+	"testing"
+)
+
+// fib computes Fibonacci numbers the slow way, spawning a task for every call above the cutoff.
+func fib(w *Worker, n int) (int, error) {
+	if n < 10 {
+		a, b := 0, 1
+		for range n {
+			a, b = b, a+b
+		}
+		return a, nil
+	}
+	left := Spawn(w, func(w *Worker) (int, error) { return fib(w, n-1) })
+	right, err := fib(w, n-2)
+	if err != nil {
+		return 0, err
+	}
+	l, err := left.Join(w)
+	return l + right, err
+}
+
+func TestForkJoin_Fibonacci(t *testing.T) {
+	for _, workers := range []int{1, 2, 8} {
+		got, err := ForkJoin(Options{}.WithWorkers(workers), func(w *Worker) (int, error) {
+			return fib(w, 25)
+		})
+		if err != nil {
+			t.Fatalf("%d workers: unexpected error: %v", workers, err)
+		}
+		if got != 75025 {
+			t.Errorf("%d workers: expected 75025, got %d", workers, got)
+		}
+	}
+}
+
+// quicksort sorts v in place, sorting the two sides of every partition in parallel.
+func quicksort(w *Worker, v []int) {
+	if len(v) < 64 {
+		slices.Sort(v)
+		return
+	}
+	pivot := v[len(v)/2]
+	lo, hi := 0, len(v)-1
+	for lo <= hi {
+		for v[lo] < pivot {
+			lo++
+		}
+		for v[hi] > pivot {
+			hi--
+		}
+		if lo <= hi {
+			v[lo], v[hi] = v[hi], v[lo]
+			lo++
+			hi--
+		}
+	}
+	left := Spawn(w, func(w *Worker) (struct{}, error) {
+		quicksort(w, v[:hi+1])
+		return struct{}{}, nil
+	})
+	quicksort(w, v[lo:])
+	_, _ = left.Join(w)
+}
+
+func TestForkJoin_Quicksort(t *testing.T) {
+	v := make([]int, 100000)
+	for i := range v {
+		v[i] = (i * 7919) % 10007
+	}
+	before := runtime.NumGoroutine()
+	var peak atomic.Int64
+	_, err := ForkJoin(Options{}.WithWorkers(4), func(w *Worker) (struct{}, error) {
+		quicksort(w, v)
+		peak.Store(int64(runtime.NumGoroutine()))
+		return struct{}{}, nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !slices.IsSorted(v) {
+		t.Error("Expected the slice to be sorted")
+	}
+	if int(peak.Load()) > before+4 {
+		t.Errorf("Expected at most 4 more goroutines than the %d before, got %d", before, peak.Load())
+	}
+}
+
+func TestForkJoin_Workers(t *testing.T) {
+	var seen [4]atomic.Int32
+	_, err := ForkJoin(Options{}.WithWorkers(4), func(w *Worker) (int, error) {
+		var tasks []*Task[int]
+		for range 1000 {
+			tasks = append(tasks, Spawn(w, func(w *Worker) (int, error) {
+				seen[w.ID()].Add(1)
+				return 0, nil
+			}))
+		}
+		for _, task := range tasks {
+			if _, err := task.Join(w); err != nil {
+				return 0, err
+			}
+		}
+		return 0, nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	total := 0
+	for i := range seen {
+		total += int(seen[i].Load())
+	}
+	if total != 1000 {
+		t.Errorf("Expected 1000 tasks to run, got %d", total)
+	}
+}
+
+func TestForkJoin_UnjoinedTasks(t *testing.T) {
+	// ForkJoin waits for tasks that are never joined
+	var ran atomic.Int32
+	_, err := ForkJoin(Options{}.WithWorkers(2), func(w *Worker) (int, error) {
+		for range 100 {
+			Spawn(w, func(*Worker) (int, error) {
+				ran.Add(1)
+				return 0, nil
+			})
+		}
+		return 0, nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if ran.Load() != 100 {
+		t.Errorf("Expected 100 tasks to run, got %d", ran.Load())
+	}
+}
+
+func TestForkJoin_Error(t *testing.T) {
+	errBad := errors.New("bad task")
+	var ran atomic.Int32
+	run := func(opts Options) error {
+		_, err := ForkJoin(opts, func(w *Worker) (int, error) {
+			failing := Spawn(w, func(*Worker) (int, error) {
+				ran.Add(1)
+				return 0, errBad
+			})
+			if _, err := failing.Join(w); err != nil {
+				// Spawned after the failure
+				later := Spawn(w, func(*Worker) (int, error) {
+					ran.Add(1)
+					return 1, nil
+				})
+				_, laterErr := later.Join(w)
+				return 0, errors.Join(err, laterErr)
+			}
+			return 0, nil
+		})
+		return err
+	}
+
+	if err := run(Options{}.WithWorkers(2)); !errors.Is(err, errBad) {
+		t.Errorf("Expected the task error, got %v", err)
+	}
+	if ran.Load() != 2 {
+		t.Errorf("Expected both tasks to run, got %d", ran.Load())
+	}
+
+	ran.Store(0)
+	if err := run(Options{}.WithWorkers(2).StopOnError(true)); !errors.Is(err, errBad) {
+		t.Errorf("Expected the task error, got %v", err)
+	}
+	if ran.Load() != 1 {
+		t.Errorf("Expected the task spawned after the failure to be skipped, got %d runs", ran.Load())
+	}
+}
+
+func BenchmarkForkJoin_Fibonacci(b *testing.B) {
+	opts := Options{}.WithWorkers(runtime.NumCPU())
+	for b.Loop() {
+		if _, err := ForkJoin(opts, func(w *Worker) (int, error) { return fib(w, 30) }); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
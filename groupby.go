@@ -0,0 +1,68 @@
+package toil
+
+// KeyFunc returns the key of an item.
+type KeyFunc[T any, K comparable] func(T) (K, error)
+
+// ParallelGroupBy groups the items of v by key. Items keep their original relative order within each group.
+// Keys are computed in parallel over contiguous blocks of v, each block grouping its items into its own
+// per-shard maps without any locking; each shard then gathers its groups from every block, in block order.
+// Errors are handled as in ParallelFilter; failed items are in no group.
+func ParallelGroupBy[T any, K comparable](v []T, key KeyFunc[T, K], opts Options) (map[K][]T, error) {
+	opts = opts.resolve(len(v))
+	groups := NewShardedMap[K, []T](opts.workers)
+	err := groupInto(len(v), func(i int) (K, T, error) {
+		k, err := key(v[i])
+		return k, v[i], err
+	}, groups, opts)
+	if err != nil && opts.stopOnError {
+		return nil, err
+	}
+	return groups.Map(), err
+}
+
+// groupInto appends the items of [0, n), as returned by item, to the groups of m under their key,
+// keeping every group in index order. m must be empty and not in use by anything else.
+func groupInto[K comparable, V any](n int, item func(int) (K, V, error), m *ShardedMap[K, []V], opts Options) error {
+	s := opts.split(n)
+	shards := len(m.shards)
+
+	// First pass: every block groups its own items into one map per shard.
+	local := make([][]map[K][]V, s.count())
+	err := forEachItem(s, opts, func(_ int, b block, i int) error {
+		k, value, err := item(i)
+		if err != nil {
+			return err
+		}
+		if local[b.index] == nil {
+			local[b.index] = make([]map[K][]V, shards)
+		}
+		shard := m.shardOf(k)
+		if local[b.index][shard] == nil {
+			local[b.index][shard] = make(map[K][]V)
+		}
+		local[b.index][shard][k] = append(local[b.index][shard][k], value)
+		return nil
+	})
+	if err != nil && opts.stopOnError {
+		return err
+	}
+
+	// Second pass: every shard is owned by one goroutine, which appends its groups from each block in order.
+	_ = forEachBlock(fixedSplit(shards, 1), opts, func(_ int, b block) error {
+		out := m.shards[b.index].m
+		for _, blockShards := range local {
+			if blockShards == nil {
+				continue
+			}
+			for k, values := range blockShards[b.index] {
+				if group, ok := out[k]; ok {
+					out[k] = append(group, values...)
+				} else {
+					out[k] = values
+				}
+			}
+		}
+		return nil
+	})
+	return err
+}
@@ -0,0 +1,70 @@
+package toil
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParallelGroupBy(t *testing.T) {
+	input := make([]int, 10000)
+	for i := range input {
+		input[i] = i
+	}
+	mod7 := func(x int) (int, error) { return x % 7, nil }
+
+	groups, err := ParallelGroupBy(input, mod7, Options{}.WithWorkers(4))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(groups) != 7 {
+		t.Fatalf("Expected 7 groups, got %d", len(groups))
+	}
+	total := 0
+	for k, group := range groups {
+		total += len(group)
+		for i, x := range group {
+			if x != k+7*i {
+				t.Fatalf("Expected group %d to keep input order, got %v at %d", k, x, i)
+			}
+		}
+	}
+	if total != len(input) {
+		t.Errorf("Expected %d grouped items, got %d", len(input), total)
+	}
+}
+
+func TestParallelGroupBy_Strings(t *testing.T) {
+	input := []string{"apple", "avocado", "banana", "blueberry", "cherry", "apricot"}
+	first := func(s string) (byte, error) { return s[0], nil }
+
+	groups, err := ParallelGroupBy(input, first, Options{}.WithWorkers(2))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := groups['a']; len(got) != 3 || got[0] != "apple" || got[1] != "avocado" || got[2] != "apricot" {
+		t.Errorf("Expected [apple avocado apricot], got %v", got)
+	}
+}
+
+func TestParallelGroupBy_Error(t *testing.T) {
+	input := []int{1, 2, 3, 4}
+	failOnThree := func(x int) (int, error) {
+		if x == 3 {
+			return 0, errors.New("fail on 3")
+		}
+		return x % 2, nil
+	}
+
+	groups, err := ParallelGroupBy(input, failOnThree, Options{}.WithWorkers(2))
+	if err == nil {
+		t.Fatal("Expected error but got none")
+	}
+	if len(groups[1]) != 1 || len(groups[0]) != 2 {
+		t.Errorf("Expected failed item to be left out, got %v", groups)
+	}
+
+	groups, err = ParallelGroupBy(input, failOnThree, Options{}.WithWorkers(2).StopOnError(true))
+	if err == nil || groups != nil {
+		t.Errorf("Expected nil groups and an error when stopping on error, got %v, %v", groups, err)
+	}
+}
@@ -0,0 +1,86 @@
+package toil
+
+import (
+	"math"
+	"math/bits"
+)
+
+// HyperLogLog estimates the number of distinct items in a set using 2^precision one-byte registers,
+// with a standard error of about 1.04/sqrt(2^precision). Items are added by hash; see HashString.
+type HyperLogLog struct {
+	precision uint8
+	registers []uint8
+}
+
+// NewHyperLogLog returns an empty HyperLogLog. precision is clamped to the range [4, 18].
+func NewHyperLogLog(precision uint8) *HyperLogLog {
+	precision = min(max(precision, 4), 18)
+	return &HyperLogLog{precision: precision, registers: make([]uint8, 1<<precision)}
+}
+
+// AddHash adds an item, given its 64-bit hash, to h.
+func (h *HyperLogLog) AddHash(hash uint64) {
+	index := hash >> (64 - h.precision)
+	// Count leading zeros of the remaining bits; the sentinel bit bounds the rank
+	rank := uint8(bits.LeadingZeros64(hash<<h.precision|1<<(h.precision-1))) + 1
+	if rank > h.registers[index] {
+		h.registers[index] = rank
+	}
+}
+
+// Count returns the estimated number of distinct items added to h.
+func (h *HyperLogLog) Count() uint64 {
+	m := float64(len(h.registers))
+	var sum float64
+	zeros := 0
+	for _, r := range h.registers {
+		sum += math.Ldexp(1, -int(r))
+		if r == 0 {
+			zeros++
+		}
+	}
+
+	var alpha float64
+	switch len(h.registers) {
+	case 16:
+		alpha = 0.673
+	case 32:
+		alpha = 0.697
+	case 64:
+		alpha = 0.709
+	default:
+		alpha = 0.7213 / (1 + 1.079/m)
+	}
+	estimate := alpha * m * m / sum
+
+	// Small cardinalities are estimated much better by linear counting
+	if estimate <= 2.5*m && zeros > 0 {
+		estimate = m * math.Log(m/float64(zeros))
+	}
+	return uint64(estimate + 0.5)
+}
+
+// Merge returns a new HyperLogLog counting the union of h and o, which must have the same precision.
+// Method expression (*HyperLogLog).Merge is a ReduceFunc.
+func (h *HyperLogLog) Merge(o *HyperLogLog) (*HyperLogLog, error) {
+	if h.precision != o.precision {
+		return nil, ErrSketchMismatch
+	}
+	merged := &HyperLogLog{precision: h.precision, registers: make([]uint8, len(h.registers))}
+	for i := range merged.registers {
+		merged.registers[i] = max(h.registers[i], o.registers[i])
+	}
+	return merged, nil
+}
+
+// HyperLogLogOf is an Aggregator estimating the number of distinct items, as hashed by hash.
+func HyperLogLogOf[T any](precision uint8, hash func(T) uint64) Aggregator[T, *HyperLogLog] {
+	return Aggregator[T, *HyperLogLog]{
+		Zero: func() *HyperLogLog { return NewHyperLogLog(precision) },
+		Add: func(acc *HyperLogLog, _ int, x T) (*HyperLogLog, error) {
+			acc.AddHash(hash(x))
+			return acc, nil
+		},
+		Merge: (*HyperLogLog).Merge,
+	}
+}
@@ -0,0 +1,53 @@
+package toil
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestHyperLogLog_Count(t *testing.T) {
+	for _, distinct := range []int{0, 10, 1000, 100000} {
+		h := NewHyperLogLog(14)
+		for i := 0; i < distinct; i++ {
+			// Add every item twice, only distinct items should count
+			h.AddHash(HashString(strconv.Itoa(i)))
+			h.AddHash(HashString(strconv.Itoa(i)))
+		}
+		estimate := float64(h.Count())
+		if math.Abs(estimate-float64(distinct)) > 0.03*float64(distinct)+1 {
+			t.Errorf("Expected about %d distinct items, estimated %v", distinct, estimate)
+		}
+	}
+}
+
+func TestHyperLogLog_ParallelAggregate(t *testing.T) {
+	input := make([]string, 200000)
+	for i := range input {
+		input[i] = "user-" + strconv.Itoa(i%50000)
+	}
+
+	h, err := ParallelAggregate(input, HyperLogLogOf(12, HashString), Options{}.WithWorkers(4))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if estimate := float64(h.Count()); math.Abs(estimate-50000) > 0.05*50000 {
+		t.Errorf("Expected about 50000 distinct users, estimated %v", estimate)
+	}
+}
+
+func TestHyperLogLog_MergeMismatch(t *testing.T) {
+	_, err := NewHyperLogLog(10).Merge(NewHyperLogLog(12))
+	if !errors.Is(err, ErrSketchMismatch) {
+		t.Fatalf("Expected ErrSketchMismatch, got %v", err)
+	}
+}
+
+func TestHashString_MatchesHashBytes(t *testing.T) {
+	for _, s := range []string{"", "a", "hello, world"} {
+		if HashString(s) != HashBytes([]byte(s)) {
+			t.Errorf("Expected HashString(%q) to match HashBytes", s)
+		}
+	}
+}
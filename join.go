@@ -0,0 +1,143 @@
+package toil
+
+import (
+	"cmp"
+	"sync/atomic"
+)
+
+// JoinKind selects which rows ParallelJoin emits.
+type JoinKind int
+
+const (
+	// InnerJoin emits a row for every pair of left and right items with equal keys.
+	InnerJoin JoinKind = iota
+	// LeftJoin emits the rows of an InnerJoin, plus a row for every left item without a match.
+	LeftJoin
+	// AntiJoin emits a row for every left item without a match.
+	AntiJoin
+)
+
+// JoinFunc builds an output row from a left item and a matching right item. ok is false, and right the
+// zero value, for left items without a match in left and anti joins.
+type JoinFunc[L, R, O any] func(left L, right R, ok bool) (O, error)
+
+// a joinPair identifies an output row by the index of its left item and of its right item, -1 if none.
+type joinPair struct {
+	left, right int
+}
+
+// ParallelJoin joins left and right on equal keys. A hash table partitioned by key is built in parallel
+// from the smaller side, and the other side probes it in parallel. Rows are always emitted in a stable
+// order: by index of the left item, then by index of the right item.
+// Errors from join are handled as in ParallelFilter: if opts has StopOnError set, nil is returned with
+// the first error; otherwise failed rows are left out and the other rows are returned with the first error.
+func ParallelJoin[L, R any, K comparable, O any](left []L, right []R, leftKey func(L) K, rightKey func(R) K, kind JoinKind, join JoinFunc[L, R, O], opts Options) ([]O, error) {
+	opts = opts.resolve(len(left) + len(right))
+	if len(left) < len(right) {
+		return joinBuildLeft(left, right, leftKey, rightKey, kind, join, opts)
+	}
+
+	// Build from the right side, then probe with the left side: every left item finds its matches in
+	// right index order, so the rows of each block of left items are already in the final order.
+	table := NewShardedMap[K, []int](opts.workers)
+	_ = groupInto(len(right), func(j int) (K, int, error) { return rightKey(right[j]), j, nil }, table, opts)
+
+	s := opts.split(len(left))
+	parts := make([][]O, s.count())
+	err := forEachItem(s, opts, func(_ int, b block, i int) error {
+		k := leftKey(left[i])
+		matches := table.shards[table.shardOf(k)].m[k]
+		if len(matches) == 0 {
+			if kind == InnerJoin {
+				return nil
+			}
+			var zero R
+			row, err := join(left[i], zero, false)
+			if err != nil {
+				return err
+			}
+			parts[b.index] = append(parts[b.index], row)
+			return nil
+		}
+		if kind == AntiJoin {
+			return nil
+		}
+
+		var firstErr error
+		for _, j := range matches {
+			row, err := join(left[i], right[j], true)
+			if err != nil {
+				if opts.stopOnError {
+					return err
+				}
+				if firstErr == nil {
+					firstErr = err
+				}
+				continue
+			}
+			parts[b.index] = append(parts[b.index], row)
+		}
+		return firstErr
+	})
+	if err != nil && opts.stopOnError {
+		return nil, err
+	}
+	return concat(parts), err
+}
+
+// joinBuildLeft is ParallelJoin building its hash table from the left side. Probing with the right side
+// finds rows in right index order, so they are sorted by left index before being emitted.
+func joinBuildLeft[L, R any, K comparable, O any](left []L, right []R, leftKey func(L) K, rightKey func(R) K, kind JoinKind, join JoinFunc[L, R, O], opts Options) ([]O, error) {
+	table := NewShardedMap[K, []int](opts.workers)
+	_ = groupInto(len(left), func(i int) (K, int, error) { return leftKey(left[i]), i, nil }, table, opts)
+
+	matched := make([]atomic.Bool, len(left))
+	s := opts.split(len(right))
+	parts := make([][]joinPair, s.count())
+	_ = forEachBlock(s, opts, func(_ int, b block) error {
+		for j := b.lo; j < b.hi; j++ {
+			k := rightKey(right[j])
+			for _, i := range table.shards[table.shardOf(k)].m[k] {
+				matched[i].Store(true)
+				if kind != AntiJoin {
+					parts[b.index] = append(parts[b.index], joinPair{left: i, right: j})
+				}
+			}
+		}
+		return nil
+	})
+
+	pairs := concat(parts)
+	if kind != InnerJoin {
+		for i := range matched {
+			if !matched[i].Load() {
+				pairs = append(pairs, joinPair{left: i, right: -1})
+			}
+		}
+	}
+	ParallelSortFunc(pairs, func(a, b joinPair) int {
+		return cmp.Or(cmp.Compare(a.left, b.left), cmp.Compare(a.right, b.right))
+	}, opts)
+
+	s = opts.split(len(pairs))
+	rows := make([][]O, s.count())
+	err := forEachItem(s, opts, func(_ int, b block, p int) error {
+		var (
+			r  R
+			ok = pairs[p].right >= 0
+		)
+		if ok {
+			r = right[pairs[p].right]
+		}
+		row, err := join(left[pairs[p].left], r, ok)
+		if err != nil {
+			return err
+		}
+		rows[b.index] = append(rows[b.index], row)
+		return nil
+	})
+	if err != nil && opts.stopOnError {
+		return nil, err
+	}
+	return concat(rows), err
+}
@@ -0,0 +1,99 @@
+package toil
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+type joinUser struct {
+	id   int
+	name string
+}
+
+type joinOrder struct {
+	user int
+	item string
+}
+
+// naiveJoin is the nested loop join ParallelJoin must agree with.
+func naiveJoin(users []joinUser, orders []joinOrder, kind JoinKind) []string {
+	var out []string
+	for _, u := range users {
+		matched := false
+		for _, o := range orders {
+			if o.user == u.id {
+				matched = true
+				if kind != AntiJoin {
+					out = append(out, u.name+":"+o.item)
+				}
+			}
+		}
+		if !matched && kind != InnerJoin {
+			out = append(out, u.name+":-")
+		}
+	}
+	return out
+}
+
+func joinRow(u joinUser, o joinOrder, ok bool) (string, error) {
+	if !ok {
+		return u.name + ":-", nil
+	}
+	return u.name + ":" + o.item, nil
+}
+
+func TestParallelJoin(t *testing.T) {
+	userKey := func(u joinUser) int { return u.id }
+	orderKey := func(o joinOrder) int { return o.user }
+
+	// Both the left and the right side get to be the smaller one
+	for _, sizes := range [][2]int{{100, 5000}, {5000, 100}, {1000, 1000}} {
+		users := make([]joinUser, sizes[0])
+		for i := range users {
+			users[i] = joinUser{id: i * 3, name: fmt.Sprintf("u%d", i)}
+		}
+		orders := make([]joinOrder, sizes[1])
+		for i := range orders {
+			orders[i] = joinOrder{user: (i * 7) % 700, item: fmt.Sprintf("o%d", i)}
+		}
+
+		for _, kind := range []JoinKind{InnerJoin, LeftJoin, AntiJoin} {
+			result, err := ParallelJoin(users, orders, userKey, orderKey, kind, joinRow, Options{}.WithWorkers(4))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			expected := naiveJoin(users, orders, kind)
+			if !slices.Equal(result, expected) {
+				t.Errorf("Sizes %v, kind %d: expected %d rows in stable order, got %d", sizes, kind, len(expected), len(result))
+			}
+		}
+	}
+}
+
+func TestParallelJoin_Error(t *testing.T) {
+	users := []joinUser{{1, "a"}, {2, "b"}, {3, "c"}}
+	orders := []joinOrder{{1, "x"}, {2, "y"}, {3, "z"}}
+	failOnB := func(u joinUser, o joinOrder, ok bool) (string, error) {
+		if u.name == "b" {
+			return "", errors.New("fail on b")
+		}
+		return joinRow(u, o, ok)
+	}
+	userKey := func(u joinUser) int { return u.id }
+	orderKey := func(o joinOrder) int { return o.user }
+
+	result, err := ParallelJoin(users, orders, userKey, orderKey, InnerJoin, failOnB, Options{}.WithWorkers(2))
+	if err == nil {
+		t.Fatal("Expected error but got none")
+	}
+	if !slices.Equal(result, []string{"a:x", "c:z"}) {
+		t.Errorf("Expected failed row to be left out, got %v", result)
+	}
+
+	result, err = ParallelJoin(users, orders, userKey, orderKey, InnerJoin, failOnB, Options{}.WithWorkers(2).StopOnError(true))
+	if err == nil || result != nil {
+		t.Errorf("Expected nil rows and an error when stopping on error, got %v, %v", result, err)
+	}
+}
@@ -1,9 +1,34 @@
 package toil
 
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrOptionType is returned when an option registered with one of the generic With* functions
+// does not match the element type of the call it is passed to.
+var ErrOptionType = errors.New("toil: option does not match element type")
+
 // The Options struct defines the configuration for parallel processing in the toil package.
 type Options struct {
-	workers     int
-	stopOnError bool
+	workers             int
+	stopOnError         bool
+	deterministic       bool
+	commutative         bool
+	grainSize           int
+	schedule            Schedule
+	sequentialCutoff    int
+	absorbing           any // func(T) bool, see WithAbsorbing
+	cost                any // func(I) float64, see WithCost
+	priority            any // func(I) int, see WithPriority
+	deadline            any // func(I) time.Time, see WithDeadline
+	skipMissedDeadlines bool
+	resources           map[string]int64
+	weight              any // func(I) int64, see WithCapacity
+	demand              any // func(I) map[string]int64, see WithResources
+	pool                *Pool
+	poolPriority        int
 }
 
 // Define the numberof workers to use. If this value is 0 or a negative value, the number of CPU cores will be used.
@@ -18,3 +43,146 @@ func (o Options) StopOnError(stopOnError bool) Options {
 	o.stopOnError = stopOnError
 	return o
 }
+
+// Define the grain size: the number of contiguous items handed to a worker at a time. Larger grains amortise
+// the cost of scheduling when the work per item is tiny; smaller grains balance uneven work better.
+// If this value is 0 or a negative value, the grain size is chosen automatically, giving each worker a few
+// chunks. ParallelReduce and ParallelReduceK ignore it, and Deterministic calls always use a fixed block size.
+func (o Options) WithGrainSize(grainSize int) Options {
+	o.grainSize = grainSize
+	return o
+}
+
+// Define the schedule: how items are handed out to workers. The default is DynamicSchedule.
+// Calls that need blocks of a fixed size, like reductions and scans, ignore it.
+func (o Options) WithSchedule(schedule Schedule) Options {
+	o.schedule = schedule
+	return o
+}
+
+// Define the sequential cutoff. Calls over at most this many items run on the calling goroutine, without
+// starting any worker, since for small inputs starting goroutines costs more than it saves.
+// The default of 0 always runs in parallel. Reductions and ParallelAggregate ignore it.
+func (o Options) WithSequentialCutoff(cutoff int) Options {
+	o.sequentialCutoff = cutoff
+	return o
+}
+
+// Define a pool to run on at the given priority. Instead of starting goroutines of its own, a call queues
+// its chunks of work on the pool, where chunks of calls with a higher priority run first; the number of
+// workers is the size of the pool. A function run on a pool must not itself wait for a call on the same
+// pool, since it holds one of its workers meanwhile. Reductions, ParallelAggregate and ForkJoin ignore it.
+func (o Options) WithPool(pool *Pool, priority int) Options {
+	o.pool = pool
+	o.poolPriority = priority
+	return o
+}
+
+// Define whether to skip items that missed their deadline, see WithDeadline. If true, an item whose
+// deadline has passed by the time a worker is free to start it is not processed: its result is the zero
+// value, and the call returns a *DeadlineError listing such items, joined with the first error of the
+// function if any. Missed deadlines don't stop the call, even with StopOnError.
+func (o Options) SkipMissedDeadlines(skip bool) Options {
+	o.skipMissedDeadlines = skip
+	return o
+}
+
+// Define whether reductions must be reproducible. If true, ParallelReduce folds fixed-size blocks of the
+// input and combines the block results in a fixed tree, so the result is bit-identical for a given input
+// whatever the number of workers or the order in which work is scheduled.
+func (o Options) Deterministic(deterministic bool) Options {
+	o.deterministic = deterministic
+	return o
+}
+
+// Define whether the reduction function is commutative as well as associative. If true, ParallelReduce
+// combines any two available partial results as soon as they exist instead of working level by level,
+// so a single slow call doesn't stall the others. The first error from the reduction function stops it,
+// whether or not StopOnError is set. Ignored if Deterministic is set, and by ReduceSeq and ReduceChan.
+func (o Options) Commutative(commutative bool) Options {
+	o.commutative = commutative
+	return o
+}
+
+// WithAbsorbing defines an absorbing predicate for reductions over T. Once any partial result
+// satisfies it (for example `false` for AND, or `0` for a product) the answer is decided:
+// no further pairs are scheduled and that partial result is returned.
+// The predicate must only match true absorbing elements of the reduction, i.e. f(z, x) == f(x, z) == z.
+func WithAbsorbing[T any](o Options, absorbing func(T) bool) Options {
+	o.absorbing = absorbing
+	return o
+}
+
+// WithCost defines an estimate of the cost of processing an item of type I, for example its size in bytes.
+// ParallelTransform then starts items from the most to the least expensive, longest-processing-time first,
+// so that one huge item at the end of the input doesn't keep a single worker busy long after the others
+// are done. Results are still returned in input order. cost is called once per item, before any item starts.
+// Items are handed out in that order from a shared queue, one at a time unless WithGrainSize is set, and
+// WithSchedule is ignored.
+func WithCost[I any](o Options, cost func(I) float64) Options {
+	o.cost = cost
+	return o
+}
+
+// WithPriority defines the priority of an item of type I. ParallelTransform then starts items from the
+// highest to the lowest priority, and by cost (see WithCost) within a priority, while still returning results
+// in input order. priority is called once per item, before any item starts. As with WithCost, items are
+// handed out in that order from a shared queue, and WithSchedule is ignored.
+// To prioritise whole calls sharing workers, run them on a Pool, see WithPool.
+func WithPriority[I any](o Options, priority func(I) int) Options {
+	o.priority = priority
+	return o
+}
+
+// WithDeadline defines the deadline of an item of type I, for example the time by which a notification is due.
+// ParallelTransform then starts items from the earliest deadline, items with the zero time having none and
+// starting last; ties are broken by priority and cost, see WithPriority and WithCost. Results are still
+// returned in input order. deadline is called once per item, before any item starts. As with WithCost,
+// items are handed out in that order from a shared queue, and WithSchedule is ignored.
+// See SkipMissedDeadlines to skip items that are already late.
+func WithDeadline[I any](o Options, deadline func(I) time.Time) Options {
+	o.deadline = deadline
+	return o
+}
+
+// WithCapacity defines a capacity shared by the items of type I of a call, for example the memory they may
+// use at once, and the weight of an item in it. ParallelTransform then only starts an item once its weight
+// fits in what the running items leave of the capacity, like a weighted semaphore, on top of the limit on
+// the number of workers. Items waiting for capacity are served in order, so a heavy item is not overtaken
+// forever by light ones. An item whose weight is more than the whole capacity fails with ErrCapacityExceeded;
+// negative weights count as 0. It is WithResources with a single resource, and replaces any set before.
+func WithCapacity[I any](o Options, capacity int64, weight func(I) int64) Options {
+	o.resources = map[string]int64{"": capacity}
+	o.weight = weight
+	o.demand = nil
+	return o
+}
+
+// WithResources generalises WithCapacity to several named resources, for example CPU slots and database
+// connections: capacities gives the capacity of each resource, and demand what an item of type I needs
+// of each, like {"cpu": 1, "db-conns": 2}. ParallelTransform only starts an item once all of it is free,
+// and takes it all at once, so items never hold some resources while waiting for others and can't
+// deadlock. Items waiting for a resource are served in order, so none is starved, while items that need
+// none of the same resources go ahead. Amounts of 0 or less are ignored; an item that needs more of a
+// resource than its capacity, or a resource missing from capacities, fails with ErrCapacityExceeded.
+// It replaces any capacity set with WithCapacity before.
+func WithResources[I any](o Options, capacities map[string]int64, demand func(I) map[string]int64) Options {
+	o.resources = capacities
+	o.demand = demand
+	o.weight = nil
+	return o
+}
+
+// optionFunc extracts a generic option stored in Options. The zero value of F is returned if
+// the option was never set, and ErrOptionType if it was set for a different element type.
+func optionFunc[F any](v any) (F, error) {
+	var zero F
+	if v == nil {
+		return zero, nil
+	}
+	f, ok := v.(F)
+	if !ok {
+		return zero, fmt.Errorf("%w: have %T, want %T", ErrOptionType, v, zero)
+	}
+	return f, nil
+}
@@ -0,0 +1,112 @@
+package toil
+
+import (
+	"cmp"
+	"slices"
+	"time"
+)
+
+// itemPlan is the order in which to start the items of a call, and what to check before starting them.
+type itemPlan struct {
+	order     []int       // Permutation of the indices of the items, nil to start them in index order
+	deadlines []time.Time // Deadline of every item if set with WithDeadline, the zero time for none
+	skip      bool        // Whether to skip items past their deadline, see SkipMissedDeadlines
+}
+
+// planItems returns the plan for the items of v: by earliest deadline set with WithDeadline, then highest
+// priority set with WithPriority, then highest cost set with WithCost, or in index order if none is set.
+func planItems[I any](v []I, opts Options) (itemPlan, error) {
+	deadline, err := optionFunc[func(I) time.Time](opts.deadline)
+	if err != nil {
+		return itemPlan{}, err
+	}
+	priority, err := optionFunc[func(I) int](opts.priority)
+	if err != nil {
+		return itemPlan{}, err
+	}
+	cost, err := optionFunc[func(I) float64](opts.cost)
+	if err != nil {
+		return itemPlan{}, err
+	}
+	if deadline == nil && priority == nil && cost == nil {
+		return itemPlan{}, nil
+	}
+
+	var (
+		p          = itemPlan{skip: deadline != nil && opts.skipMissedDeadlines}
+		priorities []int
+		costs      []float64
+	)
+	if deadline != nil {
+		p.deadlines = make([]time.Time, len(v))
+		for i, x := range v {
+			p.deadlines[i] = deadline(x)
+		}
+	}
+	if priority != nil {
+		priorities = make([]int, len(v))
+		for i, x := range v {
+			priorities[i] = priority(x)
+		}
+	}
+	if cost != nil {
+		costs = make([]float64, len(v))
+		for i, x := range v {
+			costs[i] = cost(x)
+		}
+	}
+
+	p.order = make([]int, len(v))
+	for i := range p.order {
+		p.order[i] = i
+	}
+	// Earliest deadline first, items without one last, then highest priority first, then most expensive
+	// first; ties keep their input order
+	slices.SortStableFunc(p.order, func(a, b int) int {
+		if p.deadlines != nil {
+			da, db := p.deadlines[a], p.deadlines[b]
+			switch {
+			case da.IsZero() != db.IsZero():
+				if da.IsZero() {
+					return 1
+				}
+				return -1
+			case !da.Equal(db):
+				return da.Compare(db)
+			}
+		}
+		if priorities != nil {
+			if c := cmp.Compare(priorities[b], priorities[a]); c != 0 {
+				return c
+			}
+		}
+		if costs != nil {
+			return cmp.Compare(costs[b], costs[a])
+		}
+		return 0
+	})
+	return p, nil
+}
+
+// item returns the index of the item to start at position i of the plan.
+func (p itemPlan) item(i int) int {
+	if p.order == nil {
+		return i
+	}
+	return p.order[i]
+}
+
+// missed reports whether item i is to be skipped, having missed its deadline.
+func (p itemPlan) missed(i int) bool {
+	return p.skip && !p.deadlines[i].IsZero() && time.Now().After(p.deadlines[i])
+}
+
+// split returns how to cut a call over n items following p into blocks. Items in a given order are taken
+// from a shared queue in that order, so that the order holds across workers, one at a time unless a grain
+// size is set.
+func (p itemPlan) split(n int, opts Options) split {
+	if p.order == nil {
+		return opts.split(n)
+	}
+	return fixedSplit(n, max(1, opts.grainSize))
+}
@@ -0,0 +1,142 @@
+package toil
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWithCost_Order(t *testing.T) {
+	input := []int{3, 9, 1, 9, 5}
+	var (
+		mu      sync.Mutex
+		started []int
+	)
+	record := func(x int) (int, error) {
+		mu.Lock()
+		started = append(started, x)
+		mu.Unlock()
+		return x * 2, nil
+	}
+
+	opts := WithCost(Options{}.WithWorkers(1), func(x int) float64 { return float64(x) })
+	results, err := ParallelTransform(input, record, opts)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Results stay in input order
+	expected := []int{6, 18, 2, 18, 10}
+	for i, result := range results {
+		if result != expected[i] {
+			t.Errorf("Expected result[%d] to be %d, got %d", i, expected[i], result)
+		}
+	}
+	// Items start from the most expensive
+	expectedOrder := []int{9, 9, 5, 3, 1}
+	for i, x := range started {
+		if x != expectedOrder[i] {
+			t.Errorf("Expected item %d to start with cost %d, got %d", i, expectedOrder[i], x)
+		}
+	}
+}
+
+func TestWithPriority_Order(t *testing.T) {
+	type job struct {
+		name     string
+		priority int
+		size     float64
+	}
+	input := []job{{"a", 0, 1}, {"b", 1, 1}, {"c", 0, 5}, {"d", 2, 1}, {"e", 1, 3}}
+	var started []string
+	record := func(j job) (string, error) {
+		started = append(started, j.name)
+		return j.name, nil
+	}
+
+	opts := WithPriority(Options{}.WithWorkers(1), func(j job) int { return j.priority })
+	opts = WithCost(opts, func(j job) float64 { return j.size })
+	results, err := ParallelTransform(input, record, opts)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !slices.Equal(results, []string{"a", "b", "c", "d", "e"}) {
+		t.Errorf("Expected results in input order, got %v", results)
+	}
+	// Highest priority first, then the most expensive
+	if expected := []string{"d", "e", "b", "c", "a"}; !slices.Equal(started, expected) {
+		t.Errorf("Expected items to start in order %v, got %v", expected, started)
+	}
+
+	if _, err := ParallelTransform(input, record, WithPriority(Options{}, func(int) int { return 0 })); !errors.Is(err, ErrOptionType) {
+		t.Errorf("Expected ErrOptionType, got %v", err)
+	}
+}
+
+func TestWithCost_Errors(t *testing.T) {
+	input := []int{1, 2, 3, 4}
+	errorOnEven := func(x int) (int, error) {
+		if x%2 == 0 {
+			return 0, errors.New("even number error")
+		}
+		return x, nil
+	}
+	opts := WithCost(Options{}.WithWorkers(2), func(x int) float64 { return float64(x) })
+
+	results, err := ParallelTransform(input, errorOnEven, opts)
+	if err == nil {
+		t.Fatal("Expected error but got none")
+	}
+	expected := []int{1, 0, 3, 0}
+	for i, result := range results {
+		if result != expected[i] {
+			t.Errorf("Expected result[%d] to be %d, got %d", i, expected[i], result)
+		}
+	}
+
+	results, err = ParallelTransform(input, errorOnEven, opts.StopOnError(true))
+	if err == nil || results != nil {
+		t.Errorf("Expected nil results and an error, got %v and %v", results, err)
+	}
+}
+
+func TestWithCost_TypeMismatch(t *testing.T) {
+	opts := WithCost(Options{}, func(s string) float64 { return float64(len(s)) })
+	identity := func(x int) (int, error) { return x, nil }
+	if _, err := ParallelTransform([]int{1, 2, 3}, identity, opts); !errors.Is(err, ErrOptionType) {
+		t.Errorf("Expected ErrOptionType, got %v", err)
+	}
+}
+
+// BenchmarkWithCost runs a batch in which the most expensive item comes last.
+func BenchmarkWithCost(b *testing.B) {
+	input := make([]time.Duration, 16)
+	for i := range input {
+		input[i] = time.Millisecond
+	}
+	input[len(input)-1] = 10 * time.Millisecond
+	sleep := func(d time.Duration) (time.Duration, error) {
+		time.Sleep(d)
+		return d, nil
+	}
+
+	opts := Options{}.WithWorkers(4).WithGrainSize(1)
+	b.Run("FIFO", func(b *testing.B) {
+		for b.Loop() {
+			if _, err := ParallelTransform(input, sleep, opts); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("LPT", func(b *testing.B) {
+		opts := WithCost(opts, func(d time.Duration) float64 { return float64(d) })
+		for b.Loop() {
+			if _, err := ParallelTransform(input, sleep, opts); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,124 @@
+package toil
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// ErrPoolClosed is returned when work is submitted to a Pool after Close.
+var ErrPoolClosed = errors.New("toil: pool is closed")
+
+// Pool is a long-lived set of workers shared by many calls, which run their work on it in order of
+// priority instead of starting goroutines of their own, see Submit and Options.WithPool. This way
+// interactive requests and background jobs share one worker budget, and the interactive ones go first.
+// With aging, work gains one level of priority for every aging period it waits, so that a steady stream
+// of high-priority work can't starve low-priority work forever.
+type Pool struct {
+	workers int
+	aging   time.Duration
+	start   time.Time
+
+	mu     sync.Mutex
+	ready  sync.Cond // Signalled when work is queued or the pool is closed
+	queue  []poolJob // Heap of queued work, the next to run first
+	seq    uint64    // Number of jobs queued so far
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// poolJob is work queued on a Pool. Jobs run by decreasing key, then in the order they were queued.
+type poolJob struct {
+	key float64 // Priority, aged as of the start of the pool
+	seq uint64
+	run func(worker int)
+}
+
+func (a poolJob) before(b poolJob) bool {
+	if a.key != b.key {
+		return a.key > b.key
+	}
+	return a.seq < b.seq
+}
+
+// NewPool starts a pool of workers goroutines; if workers is 0 or negative, the number of CPU cores is used.
+// If aging is positive, queued work gains one level of priority for every aging period it waits.
+// The pool runs until Close is called.
+func NewPool(workers int, aging time.Duration) *Pool {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	p := &Pool{workers: workers, aging: aging, start: time.Now()}
+	p.ready.L = &p.mu
+	for id := range workers {
+		p.wg.Add(1)
+		go p.work(id)
+	}
+	return p
+}
+
+// Workers returns the number of workers of p.
+func (p *Pool) Workers() int {
+	return p.workers
+}
+
+// Submit queues fn to run on p at the given priority: higher priorities run first, and equal ones in the
+// order they were submitted. It returns ErrPoolClosed if p is closed.
+func (p *Pool) Submit(priority int, fn func()) error {
+	return p.submit(priority, func(int) { fn() })
+}
+
+// Close stops p from accepting work, and waits for the work already queued to finish.
+func (p *Pool) Close() {
+	p.mu.Lock()
+	p.closed = true
+	p.ready.Broadcast()
+	p.mu.Unlock()
+	p.wg.Wait()
+}
+
+// submit queues run, which is given the number of the worker running it.
+func (p *Pool) submit(priority int, run func(worker int)) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return ErrPoolClosed
+	}
+
+	// Comparing aged priorities at any time t, priority + (t - enqueued)/aging, comes down to
+	// comparing priority - enqueued/aging, which doesn't change as the job waits
+	key := float64(priority)
+	if p.aging > 0 {
+		key -= float64(time.Since(p.start)) / float64(p.aging)
+	}
+	p.queue = append(p.queue, poolJob{key: key, seq: p.seq, run: run})
+	p.seq++
+	siftUp(p.queue, len(p.queue)-1, poolJob.before)
+	p.ready.Signal()
+	return nil
+}
+
+// work runs queued jobs on the worker id until p is closed and its queue is empty.
+func (p *Pool) work(id int) {
+	defer p.wg.Done()
+	for {
+		p.mu.Lock()
+		for len(p.queue) == 0 && !p.closed {
+			p.ready.Wait()
+		}
+		if len(p.queue) == 0 {
+			p.mu.Unlock()
+			return
+		}
+		job := p.queue[0]
+		last := len(p.queue) - 1
+		p.queue[0] = p.queue[last]
+		p.queue[last] = poolJob{}
+		p.queue = p.queue[:last]
+		siftDown(p.queue, 0, poolJob.before)
+		p.mu.Unlock()
+
+		job.run(id)
+	}
+}
@@ -0,0 +1,211 @@
+package toil
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// blockPool occupies the only worker of p until the returned function is called.
+func blockPool(t *testing.T, p *Pool) func() {
+	gate := make(chan struct{})
+	started := make(chan struct{})
+	if err := p.Submit(0, func() { close(started); <-gate }); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	<-started
+	return func() { close(gate) }
+}
+
+func TestPool_Priority(t *testing.T) {
+	p := NewPool(1, 0)
+	defer p.Close()
+	release := blockPool(t, p)
+
+	var (
+		mu    sync.Mutex
+		order []int
+	)
+	var wg sync.WaitGroup
+	for _, priority := range []int{1, 5, 3, 5, 0} {
+		wg.Add(1)
+		err := p.Submit(priority, func() {
+			defer wg.Done()
+			mu.Lock()
+			order = append(order, priority)
+			mu.Unlock()
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	release()
+	wg.Wait()
+
+	if expected := []int{5, 5, 3, 1, 0}; !slices.Equal(order, expected) {
+		t.Errorf("Expected jobs to run in order %v, got %v", expected, order)
+	}
+}
+
+func TestPool_Aging(t *testing.T) {
+	p := NewPool(1, time.Millisecond)
+	defer p.Close()
+	release := blockPool(t, p)
+
+	var (
+		mu    sync.Mutex
+		order []string
+	)
+	var wg sync.WaitGroup
+	submit := func(name string, priority int) {
+		wg.Add(1)
+		err := p.Submit(priority, func() {
+			defer wg.Done()
+			mu.Lock()
+			order = append(order, name)
+			mu.Unlock()
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	// The backfill has waited for far more than 5 aging periods by the time the request arrives
+	submit("backfill", 0)
+	time.Sleep(20 * time.Millisecond)
+	submit("request", 5)
+	release()
+	wg.Wait()
+
+	if expected := []string{"backfill", "request"}; !slices.Equal(order, expected) {
+		t.Errorf("Expected jobs to run in order %v, got %v", expected, order)
+	}
+}
+
+func TestPool_Close(t *testing.T) {
+	p := NewPool(2, 0)
+	var ran atomic.Int32
+	for range 100 {
+		if err := p.Submit(0, func() { ran.Add(1) }); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	p.Close()
+
+	if ran.Load() != 100 {
+		t.Errorf("Expected Close to wait for all 100 jobs, %d ran", ran.Load())
+	}
+	if err := p.Submit(0, func() {}); !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("Expected ErrPoolClosed, got %v", err)
+	}
+	if _, err := ParallelTransform([]int{1, 2, 3}, func(x int) (int, error) { return x, nil }, Options{}.WithPool(p, 0)); !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("Expected ErrPoolClosed, got %v", err)
+	}
+}
+
+func TestWithPool(t *testing.T) {
+	p := NewPool(3, 0)
+	defer p.Close()
+
+	input := make([]int, 1000)
+	for i := range input {
+		input[i] = i
+	}
+	square := func(x int) (int, error) {
+		return x * x, nil
+	}
+	results, err := ParallelTransform(input, square, Options{}.WithWorkers(16).WithPool(p, 0))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for i, result := range results {
+		if result != i*i {
+			t.Fatalf("Expected result[%d] to be %d, got %d", i, i*i, result)
+		}
+	}
+
+	// Per-worker state is indexed by the number of the pool worker
+	top := ParallelTopK(input, 10, func(a, b int) bool {
+		return a < b
+	}, Options{}.WithPool(p, 0))
+	if !slices.Equal(top, []int{999, 998, 997, 996, 995, 994, 993, 992, 991, 990}) {
+		t.Errorf("Unexpected top 10: %v", top)
+	}
+}
+
+func TestWithPool_FindFirst(t *testing.T) {
+	p := NewPool(8, 0)
+	defer p.Close()
+
+	// Every item matches, so any block but the first can find a match before the first one runs
+	input := make([]int, 64)
+	match := func(int) (bool, error) { return true, nil }
+	opts := Options{}.WithGrainSize(1).WithPool(p, 0)
+	for range 2000 {
+		i, err := ParallelFindFirst(input, match, opts)
+		if err != nil || i != 0 {
+			t.Fatalf("Expected index 0, got %d and %v", i, err)
+		}
+	}
+}
+
+func TestWithPool_Priority(t *testing.T) {
+	p := NewPool(1, 0)
+	defer p.Close()
+	release := blockPool(t, p)
+
+	var (
+		mu    sync.Mutex
+		order []string
+	)
+	record := func(name string) TransformFunc[int, int] {
+		return func(x int) (int, error) {
+			mu.Lock()
+			order = append(order, name)
+			mu.Unlock()
+			return x, nil
+		}
+	}
+	input := []int{1, 2, 3}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_, _ = ParallelTransform(input, record("backfill"), Options{}.WithGrainSize(1).WithPool(p, 0))
+	}()
+	// Wait for the backfill to be queued before the request
+	for {
+		p.mu.Lock()
+		queued := len(p.queue)
+		p.mu.Unlock()
+		if queued == len(input) {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_, _ = ParallelTransform(input, record("request"), Options{}.WithGrainSize(1).WithPool(p, 10))
+	}()
+	for {
+		p.mu.Lock()
+		queued := len(p.queue)
+		p.mu.Unlock()
+		if queued == 2*len(input) {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	release()
+	wg.Wait()
+
+	expected := []string{"request", "request", "request", "backfill", "backfill", "backfill"}
+	if !slices.Equal(order, expected) {
+		t.Errorf("Expected items to run in order %v, got %v", expected, order)
+	}
+}
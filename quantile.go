@@ -0,0 +1,203 @@
+package toil
+
+import (
+	"math"
+	"slices"
+)
+
+// QuantileSketch estimates quantiles of a stream of numbers in bounded memory, using the KLL sketch
+// of Karnin, Lang and Liberty. Larger values of k give more accurate ranks: the rank error is roughly
+// proportional to 1/k, about 1.7% for k = 200. Coin flips are drawn from a fixed seed, so the same
+// inputs added and merged in the same order always give the same sketch.
+type QuantileSketch struct {
+	k          int
+	compactors [][]float64 // Items at level h each stand for 2^h inputs
+	count      uint64
+	seed       uint64
+}
+
+// NewQuantileSketch returns an empty quantile sketch. k is at least 8.
+func NewQuantileSketch(k int) *QuantileSketch {
+	return &QuantileSketch{k: max(k, 8), compactors: make([][]float64, 1), seed: 0x9e3779b97f4a7c15}
+}
+
+// capacity returns how many items level h holds before it is compacted. Lower levels shrink
+// geometrically, so total memory is O(k) whatever the number of inputs.
+func (s *QuantileSketch) capacity(h int) int {
+	depth := len(s.compactors) - h - 1
+	return int(math.Ceil(float64(s.k)*math.Pow(2.0/3.0, float64(depth)))) + 1
+}
+
+func (s *QuantileSketch) size() int {
+	n := 0
+	for _, c := range s.compactors {
+		n += len(c)
+	}
+	return n
+}
+
+func (s *QuantileSketch) maxSize() int {
+	n := 0
+	for h := range s.compactors {
+		n += s.capacity(h)
+	}
+	return n
+}
+
+// coin returns a pseudo-random bit from the sketch's xorshift generator.
+func (s *QuantileSketch) coin() int {
+	s.seed ^= s.seed << 13
+	s.seed ^= s.seed >> 7
+	s.seed ^= s.seed << 17
+	return int(s.seed & 1)
+}
+
+// compress compacts full levels, promoting every other item of a sorted level to the level above,
+// until the sketch fits in its capacity again.
+func (s *QuantileSketch) compress() {
+	for h := 0; h < len(s.compactors); h++ {
+		if len(s.compactors[h]) < s.capacity(h) {
+			continue
+		}
+		if h+1 == len(s.compactors) {
+			s.compactors = append(s.compactors, nil)
+		}
+
+		level := s.compactors[h]
+		slices.Sort(level)
+		// An odd item out stays behind, so the promoted items have exactly twice the weight
+		var kept []float64
+		if len(level)%2 == 1 {
+			kept = append(kept, level[len(level)-1])
+			level = level[:len(level)-1]
+		}
+		for i := s.coin(); i < len(level); i += 2 {
+			s.compactors[h+1] = append(s.compactors[h+1], level[i])
+		}
+		s.compactors[h] = append(level[:0], kept...)
+
+		if s.size() < s.maxSize() {
+			return
+		}
+	}
+}
+
+// Add adds x to the sketch.
+func (s *QuantileSketch) Add(x float64) {
+	s.compactors[0] = append(s.compactors[0], x)
+	s.count++
+	if s.size() >= s.maxSize() {
+		s.compress()
+	}
+}
+
+// Count returns the number of values added to the sketch.
+func (s *QuantileSketch) Count() uint64 {
+	return s.count
+}
+
+// a weighted is an item of the sketch along with the number of inputs it stands for.
+type weighted struct {
+	value  float64
+	weight uint64
+}
+
+func (s *QuantileSketch) weighted() []weighted {
+	var items []weighted
+	for h, c := range s.compactors {
+		for _, x := range c {
+			items = append(items, weighted{value: x, weight: 1 << h})
+		}
+	}
+	slices.SortFunc(items, func(a, b weighted) int {
+		if a.value < b.value {
+			return -1
+		}
+		if a.value > b.value {
+			return 1
+		}
+		return 0
+	})
+	return items
+}
+
+// Quantile returns an estimate of the q-quantile of the values added, for 0 <= q <= 1;
+// for example Quantile(0.99) is the 99th percentile. It returns NaN if the sketch is empty.
+func (s *QuantileSketch) Quantile(q float64) float64 {
+	items := s.weighted()
+	if len(items) == 0 {
+		return math.NaN()
+	}
+	var total uint64
+	for _, item := range items {
+		total += item.weight
+	}
+	target := q * float64(total)
+	var seen uint64
+	for _, item := range items {
+		seen += item.weight
+		if float64(seen) >= target {
+			return item.value
+		}
+	}
+	return items[len(items)-1].value
+}
+
+// Rank returns an estimate of the fraction of values added which are less than or equal to x.
+func (s *QuantileSketch) Rank(x float64) float64 {
+	var below, total uint64
+	for h, c := range s.compactors {
+		for _, y := range c {
+			if y <= x {
+				below += 1 << h
+			}
+			total += 1 << h
+		}
+	}
+	if total == 0 {
+		return math.NaN()
+	}
+	return float64(below) / float64(total)
+}
+
+// Merge returns a new sketch summarising the values of both s and o, which must have the same k.
+// Method expression (*QuantileSketch).Merge is a ReduceFunc.
+func (s *QuantileSketch) Merge(o *QuantileSketch) (*QuantileSketch, error) {
+	if s.k != o.k {
+		return nil, ErrSketchMismatch
+	}
+	merged := &QuantileSketch{
+		k:          s.k,
+		compactors: make([][]float64, max(len(s.compactors), len(o.compactors))),
+		count:      s.count + o.count,
+		seed:       s.seed ^ mix64(o.seed),
+	}
+	if merged.seed == 0 {
+		// xorshift never leaves zero
+		merged.seed = 0x9e3779b97f4a7c15
+	}
+	for h := range merged.compactors {
+		if h < len(s.compactors) {
+			merged.compactors[h] = append(merged.compactors[h], s.compactors[h]...)
+		}
+		if h < len(o.compactors) {
+			merged.compactors[h] = append(merged.compactors[h], o.compactors[h]...)
+		}
+	}
+	for merged.size() >= merged.maxSize() {
+		merged.compress()
+	}
+	return merged, nil
+}
+
+// QuantilesOf is an Aggregator building a quantile sketch of numbers; see QuantileSketch.
+func QuantilesOf[T Number](k int) Aggregator[T, *QuantileSketch] {
+	return Aggregator[T, *QuantileSketch]{
+		Zero: func() *QuantileSketch { return NewQuantileSketch(k) },
+		Add: func(acc *QuantileSketch, _ int, x T) (*QuantileSketch, error) {
+			acc.Add(float64(x))
+			return acc, nil
+		},
+		Merge: (*QuantileSketch).Merge,
+	}
+}
@@ -0,0 +1,60 @@
+package toil
+
+import (
+	"errors"
+	"math"
+	"math/rand/v2"
+	"testing"
+)
+
+func TestQuantileSketch_Quantile(t *testing.T) {
+	s := NewQuantileSketch(200)
+	r := rand.New(rand.NewPCG(1, 2))
+	for _, i := range r.Perm(100000) {
+		s.Add(float64(i))
+	}
+
+	if s.Count() != 100000 {
+		t.Errorf("Expected count 100000, got %d", s.Count())
+	}
+	for _, q := range []float64{0.01, 0.5, 0.9, 0.99} {
+		estimate := s.Quantile(q)
+		if math.Abs(estimate-q*100000) > 0.03*100000 {
+			t.Errorf("Expected quantile %v close to %v, got %v", q, q*100000, estimate)
+		}
+	}
+	if rank := s.Rank(25000); math.Abs(rank-0.25) > 0.03 {
+		t.Errorf("Expected rank of 25000 close to 0.25, got %v", rank)
+	}
+}
+
+func TestQuantileSketch_ParallelAggregate(t *testing.T) {
+	input := make([]int, 200000)
+	for i := range input {
+		input[i] = (i * 7919) % len(input)
+	}
+
+	s, err := ParallelAggregate(input, QuantilesOf[int](200), Options{}.WithWorkers(8))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if s.Count() != uint64(len(input)) {
+		t.Errorf("Expected count %d, got %d", len(input), s.Count())
+	}
+	if p99 := s.Quantile(0.99); math.Abs(p99-0.99*200000) > 0.03*200000 {
+		t.Errorf("Expected p99 close to %v, got %v", 0.99*200000, p99)
+	}
+}
+
+func TestQuantileSketch_Empty(t *testing.T) {
+	if !math.IsNaN(NewQuantileSketch(100).Quantile(0.5)) {
+		t.Errorf("Expected NaN quantile for empty sketch")
+	}
+}
+
+func TestQuantileSketch_MergeMismatch(t *testing.T) {
+	_, err := NewQuantileSketch(100).Merge(NewQuantileSketch(200))
+	if !errors.Is(err, ErrSketchMismatch) {
+		t.Fatalf("Expected ErrSketchMismatch, got %v", err)
+	}
+}
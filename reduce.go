@@ -1,7 +1,10 @@
 package toil
 
 import (
+	"errors"
+	"fmt"
 	"runtime"
+	"strings"
 	"sync"
 	"sync/atomic"
 )
@@ -11,12 +14,71 @@ import (
 // If this function returns an error, the reduction will stop, the error is returned.
 type ReduceFunc[T any] func(T, T) (T, error)
 
+// A ReduceKFunc combines a group of adjacent values of T, in order, into one.
+// The group slice is only valid for the duration of the call and must not be modified or retained.
+type ReduceKFunc[T any] func([]T) (T, error)
+
+// ErrInvalidFanIn is returned by ParallelReduceK when fanIn is less than 2.
+var ErrInvalidFanIn = errors.New("toil: fanIn must be at least 2")
+
+// Range is a half-open range [Lo, Hi) of input indices.
+type Range struct {
+	Lo, Hi int
+}
+
+func (r Range) String() string {
+	return fmt.Sprintf("[%d,%d)", r.Lo, r.Hi)
+}
+
+// ReduceError reports a failed call to a reduction function, so a bad record can be traced back to its inputs.
+// Level is the depth of the failed call in the reduction: calls at level 0 combine input values directly,
+// and calls at each level above combine the results of the level below.
+// Operands holds, for each operand of the failed call in order, the ranges of input indices it covers.
+// In tree reductions every operand covers a single range; in Commutative reductions it may cover several.
+type ReduceError struct {
+	Level    int
+	Operands [][]Range
+	Err      error
+}
+
+func (e *ReduceError) Error() string {
+	var operands []string
+	for _, ranges := range e.Operands {
+		var parts []string
+		for _, r := range ranges {
+			parts = append(parts, r.String())
+		}
+		operands = append(operands, strings.Join(parts, "+"))
+	}
+	return fmt.Sprintf("toil: reduce failed at level %d combining %s: %v", e.Level, strings.Join(operands, " "), e.Err)
+}
+
+func (e *ReduceError) Unwrap() error {
+	return e.Err
+}
+
 // ParallelReduce applies a binary function to reduce a slice to a single value in parallel.
 // The function f should be associative for correct results. The reduction is performed in parallel
 // using the number of workers specified in opts. If the slice is empty, returns an error.
+//
+// If an absorbing predicate was set with WithAbsorbing, the reduction short-circuits as soon as
+// any input or partial result satisfies it: remaining pairs are not scheduled, calls already in
+// flight are allowed to finish, and the absorbing value is returned.
+//
+// If opts is Deterministic, the shape of the reduction only depends on len(v); see Options.Deterministic.
+// Otherwise, if opts is Commutative, partial results are combined in whatever order they become available.
+//
+// Errors returned by f are wrapped in a *ReduceError. If opts has StopOnError set, no further pairs are
+// scheduled once an error occurs; otherwise the current level is finished before the error is returned.
+// Commutative reductions have no levels, and always stop calling f at the first error, with or without
+// StopOnError; calls already in flight are allowed to finish.
 
 func ParallelReduce[T any](v []T, f ReduceFunc[T], opts Options) (T, error) {
 	var zero T
+	absorbing, err := optionFunc[func(T) bool](opts.absorbing)
+	if err != nil {
+		return zero, err
+	}
 	n := len(v)
 	if n == 0 {
 		return zero, nil // or return error if you want to disallow empty input
@@ -28,50 +90,281 @@ func ParallelReduce[T any](v []T, f ReduceFunc[T], opts Options) (T, error) {
 		opts.workers = runtime.NumCPU()
 	}
 
-	items := v
-	for len(items) > 1 {
+	if opts.deterministic {
+		return deterministicReduce(v, f, absorbing, opts)
+	}
+	if opts.commutative {
+		return commutativeReduce(v, f, absorbing, opts)
+	}
+	return reduceTree(v, f, absorbing, treeLayout{size: 1, total: n}, opts)
+}
+
+// ParallelReduceK reduces a slice to a single value by combining up to fanIn adjacent values at a time,
+// building a k-ary tree instead of the binary pairing of ParallelReduce. This suits operations that are
+// much cheaper over many inputs at once, like k-way merges of sorted runs or concatenating buffers.
+// Groups are always contiguous and in input order, so f only needs to be associative, not commutative.
+// Absorbing predicates set with WithAbsorbing apply as in ParallelReduce; Commutative is ignored, and
+// the tree shape only ever depends on len(v) and fanIn.
+func ParallelReduceK[T any](v []T, fanIn int, f ReduceKFunc[T], opts Options) (T, error) {
+	var zero T
+	if fanIn < 2 {
+		return zero, ErrInvalidFanIn
+	}
+	absorbing, err := optionFunc[func(T) bool](opts.absorbing)
+	if err != nil {
+		return zero, err
+	}
+	if len(v) == 0 {
+		return zero, nil
+	}
+	if opts.workers <= 0 {
+		opts.workers = runtime.NumCPU()
+	}
+	return reduceTreeK(v, fanIn, f, absorbing, treeLayout{size: 1, total: len(v)}, opts)
+}
+
+// treeLayout describes how the items handed to reduceTreeK map back to the original input: each one
+// covers size consecutive inputs (the last possibly fewer) out of total, and combining them is a call at level.
+type treeLayout struct {
+	size  int
+	total int
+	level int
+}
+
+// covers returns the range of inputs covered by item i of a level whose items each cover width inputs.
+func (l treeLayout) covers(i, width int) []Range {
+	return []Range{{Lo: i * width, Hi: min((i+1)*width, l.total)}}
+}
+
+// reduceTree combines adjacent pairs of items level by level until a single value remains.
+func reduceTree[T any](items []T, f ReduceFunc[T], absorbing func(T) bool, layout treeLayout, opts Options) (T, error) {
+	pair := func(group []T) (T, error) { return f(group[0], group[1]) }
+	return reduceTreeK(items, 2, pair, absorbing, layout, opts)
+}
+
+// reduceTreeK combines adjacent groups of up to fanIn items level by level until a single value remains.
+// A trailing group of a single item is carried to the next level as is.
+// Items at level 0 are inputs and are tested against the absorbing predicate as well as the partial results.
+func reduceTreeK[T any](items []T, fanIn int, f ReduceKFunc[T], absorbing func(T) bool, layout treeLayout, opts Options) (T, error) {
+	var zero T
+	width := layout.size // Number of inputs covered by each item of the current level
+	for level := layout.level; len(items) > 1; level, width = level+1, width*fanIn {
 		// Pre-allocate next slice with exact capacity to eliminate reallocations
-		nextCap := (len(items) + 1) / 2  // Ceiling division for pair count
-		next := make([]T, nextCap)       // Pre-allocated with exact size (not just capacity)
-		
+		nextCap := (len(items) + fanIn - 1) / fanIn // Ceiling division for group count
+		next := make([]T, nextCap)                  // Pre-allocated with exact size (not just capacity)
+
 		var (
 			wg       sync.WaitGroup
-			firstErr atomic.Pointer[error]  // Lock-free error storage
+			firstErr atomic.Pointer[error] // Lock-free error storage
+			absorbed atomic.Pointer[T]     // First partial result matching the absorbing predicate
 		)
 		sem := make(chan struct{}, opts.workers)
 
-		for i := 0; i < len(items)-1; i += 2 {
-			wg.Add(1)
+		// Inputs have not been checked yet; partial results of later levels already have been.
+		checkOperands := absorbing != nil && level == 0
+
+		for i := 0; i < len(items); i += fanIn {
+			group := items[i:min(i+fanIn, len(items))]
+
+			// Handle a lone trailing item outside goroutines (no mutex needed)
+			if len(group) == 1 {
+				if checkOperands && absorbing(group[0]) {
+					absorbed.CompareAndSwap(nil, &group[0])
+				}
+				next[nextCap-1] = group[0]
+				break
+			}
+
+			// Wait for a free worker before deciding whether to schedule the group
 			sem <- struct{}{}
-			
-			go func(a, b T, resultIndex int) {
+			if absorbed.Load() != nil || (opts.stopOnError && firstErr.Load() != nil) {
+				// The answer is decided, don't schedule any more groups
+				<-sem
+				break
+			}
+			wg.Add(1)
+
+			go func(group []T, first int) {
 				defer wg.Done()
 				defer func() { <-sem }()
-				
-				res, err := f(a, b)
+
+				if checkOperands {
+					for j := range group {
+						if absorbing(group[j]) {
+							absorbed.CompareAndSwap(nil, &group[j])
+							return
+						}
+					}
+				}
+
+				res, err := f(group)
 				if err != nil {
+					operands := make([][]Range, len(group))
+					for j := range group {
+						operands[j] = layout.covers(first+j, width)
+					}
+					var reduceErr error = &ReduceError{Level: level, Operands: operands, Err: err}
 					// Lock-free: only first error wins, others ignored
-					firstErr.CompareAndSwap(nil, &err)
+					firstErr.CompareAndSwap(nil, &reduceErr)
+				} else if absorbing != nil && absorbing(res) {
+					absorbed.CompareAndSwap(nil, &res)
 				}
 				// Lock-free: direct indexed write, no contention
-				next[resultIndex] = res
-				
-			}(items[i], items[i+1], i/2)
-		}
-		
-		// Handle odd element outside goroutines (no mutex needed)
-		if len(items)%2 == 1 {
-			next[nextCap-1] = items[len(items)-1]
+				next[first/fanIn] = res
+
+			}(group, i)
 		}
-		
+
 		wg.Wait()
-		
+
 		// Check for any errors after all work complete
 		if errPtr := firstErr.Load(); errPtr != nil {
 			return zero, *errPtr
 		}
-		
+		if res := absorbed.Load(); res != nil {
+			return *res, nil
+		}
+
 		items = next
 	}
 	return items[0], nil
 }
+
+// commutativeReduce combines partial results from a shared queue: each worker takes any two available
+// partials and pushes their combination back, until the n-1 combinations have all been made.
+// There are no per-level barriers, so one slow call only holds up the two partials it is combining.
+func commutativeReduce[T any](v []T, f ReduceFunc[T], absorbing func(T) bool, opts Options) (T, error) {
+	var zero T
+	n := len(v)
+
+	// a commutativePartial tracks which inputs went into a partial result, for error reporting.
+	type commutativePartial struct {
+		value  T
+		covers []Range
+		level  int
+	}
+
+	// The queue never holds more than the n inputs: every combination takes two partials and returns one.
+	partials := make(chan commutativePartial, n)
+	for i, x := range v {
+		if absorbing != nil && absorbing(x) {
+			return x, nil
+		}
+		partials <- commutativePartial{value: x, covers: []Range{{Lo: i, Hi: i + 1}}}
+	}
+
+	var (
+		wg       sync.WaitGroup
+		claimed  atomic.Int64
+		firstErr atomic.Pointer[error]
+		absorbed atomic.Pointer[T]
+	)
+	merges := int64(n - 1)
+
+	for range min(opts.workers, n-1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Claiming a combination before taking from the queue guarantees two partials will
+			// eventually be available for every claim, so workers can never deadlock waiting.
+			for claimed.Add(1) <= merges {
+				a := <-partials
+				b := <-partials
+				if firstErr.Load() != nil || absorbed.Load() != nil {
+					// The result is decided, pass partials through without calling f. Without levels
+					// there is no natural point to stop at, so errors stop the reduction even without
+					// StopOnError
+					partials <- a
+					continue
+				}
+
+				res, err := f(a.value, b.value)
+				level := max(a.level, b.level)
+				if err != nil {
+					var reduceErr error = &ReduceError{Level: level, Operands: [][]Range{a.covers, b.covers}, Err: err}
+					firstErr.CompareAndSwap(nil, &reduceErr)
+				} else if absorbing != nil && absorbing(res) {
+					absorbed.CompareAndSwap(nil, &res)
+				}
+				partials <- commutativePartial{value: res, covers: mergeRanges(a.covers, b.covers), level: level + 1}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if errPtr := firstErr.Load(); errPtr != nil {
+		return zero, *errPtr
+	}
+	if res := absorbed.Load(); res != nil {
+		return *res, nil
+	}
+	return (<-partials).value, nil
+}
+
+// mergeRanges returns the union of two sorted, disjoint lists of ranges, coalescing adjacent ranges.
+func mergeRanges(a, b []Range) []Range {
+	merged := make([]Range, 0, len(a)+len(b))
+	for len(a) > 0 || len(b) > 0 {
+		var r Range
+		if len(b) == 0 || (len(a) > 0 && a[0].Lo < b[0].Lo) {
+			r, a = a[0], a[1:]
+		} else {
+			r, b = b[0], b[1:]
+		}
+		if last := len(merged) - 1; last >= 0 && merged[last].Hi == r.Lo {
+			merged[last].Hi = r.Hi
+		} else {
+			merged = append(merged, r)
+		}
+	}
+	return merged
+}
+
+// foldBlocks splits v into consecutive blocks of size items and folds each block into a partial
+// result, running up to opts.workers folds at once. The partial results are returned in block order.
+// fold is given the index of the first item of its block, and reports whether its partial result is
+// absorbing, in which case no further blocks are scheduled and that result is returned as absorbed.
+// If opts has StopOnError set, no further blocks are scheduled once a fold fails.
+func foldBlocks[T, A any](v []T, size int, opts Options, fold func(int, []T) (A, bool, error)) (partials []A, absorbed *A, err error) {
+	blocks := (len(v) + size - 1) / size
+	partials = make([]A, blocks)
+
+	var (
+		wg        sync.WaitGroup
+		firstErr  atomic.Pointer[error]
+		absorbing atomic.Pointer[A]
+	)
+	sem := make(chan struct{}, opts.workers)
+
+	for b := 0; b < blocks; b++ {
+		sem <- struct{}{}
+		if absorbing.Load() != nil || (opts.stopOnError && firstErr.Load() != nil) {
+			<-sem
+			break
+		}
+		wg.Add(1)
+
+		go func(b int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			lo := b * size
+			hi := min(lo+size, len(v))
+			res, isAbsorbing, err := fold(lo, v[lo:hi])
+			if err != nil {
+				firstErr.CompareAndSwap(nil, &err)
+			} else if isAbsorbing {
+				absorbing.CompareAndSwap(nil, &res)
+			}
+			partials[b] = res
+		}(b)
+	}
+
+	wg.Wait()
+
+	if errPtr := firstErr.Load(); errPtr != nil {
+		return nil, nil, *errPtr
+	}
+	return partials, absorbing.Load(), nil
+}
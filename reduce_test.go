@@ -5,7 +5,10 @@ import (
 	"fmt"
 	"math"
 	"runtime"
+	"slices"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestParallelReduce_Sum(t *testing.T) {
@@ -102,6 +105,188 @@ func TestParallelReduce_ParallelCorrectness(t *testing.T) {
 	}
 }
 
+func TestParallelReduce_Absorbing(t *testing.T) {
+	input := make([]int, 1000)
+	for i := range input {
+		input[i] = i + 1
+	}
+	input[500] = 0
+
+	var calls atomic.Int64
+	prodFunc := func(a, b int) (int, error) {
+		calls.Add(1)
+		return a * b, nil
+	}
+	opts := WithAbsorbing(Options{}.WithWorkers(2), func(x int) bool { return x == 0 })
+	result, err := ParallelReduce(input, prodFunc, opts)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result != 0 {
+		t.Errorf("Expected absorbing result 0, got %d", result)
+	}
+	if calls.Load() >= int64(len(input)-1) {
+		t.Errorf("Expected reduction to short-circuit, made %d calls", calls.Load())
+	}
+}
+
+func TestParallelReduce_AbsorbingPartialResult(t *testing.T) {
+	input := []bool{true, true, true, false, true, true, true}
+	andFunc := func(a, b bool) (bool, error) { return a && b, nil }
+	opts := WithAbsorbing(Options{}.WithWorkers(3), func(x bool) bool { return !x })
+	result, err := ParallelReduce(input, andFunc, opts)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result {
+		t.Errorf("Expected false, got %v", result)
+	}
+}
+
+func TestParallelReduce_AbsorbingWrongType(t *testing.T) {
+	input := []int{1, 2, 3}
+	sumFunc := func(a, b int) (int, error) { return a + b, nil }
+	opts := WithAbsorbing(Options{}, func(x string) bool { return x == "" })
+	_, err := ParallelReduce(input, sumFunc, opts)
+	if !errors.Is(err, ErrOptionType) {
+		t.Fatalf("Expected ErrOptionType, got %v", err)
+	}
+}
+
+func TestParallelReduce_Commutative(t *testing.T) {
+	sumFunc := func(a, b int) (int, error) { return a + b, nil }
+	for _, size := range []int{2, 3, 10, 999, 1000} {
+		input := make([]int, size)
+		expected := 0
+		for i := range input {
+			input[i] = i + 1
+			expected += i + 1
+		}
+		opts := Options{}.WithWorkers(4).Commutative(true)
+		result, err := ParallelReduce(input, sumFunc, opts)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result != expected {
+			t.Errorf("Size %d: expected sum %d, got %d", size, expected, result)
+		}
+	}
+}
+
+func TestParallelReduce_CommutativeError(t *testing.T) {
+	input := make([]int, 100)
+	for i := range input {
+		input[i] = i
+	}
+	errFunc := func(a, b int) (int, error) {
+		if a == 42 || b == 42 {
+			return 0, errors.New("fail on 42")
+		}
+		return a + b, nil
+	}
+	opts := Options{}.WithWorkers(4).Commutative(true)
+	_, err := ParallelReduce(input, errFunc, opts)
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+}
+
+func TestParallelReduce_CommutativeAbsorbing(t *testing.T) {
+	input := []bool{true, true, true, true, false, true, true}
+	andFunc := func(a, b bool) (bool, error) { return a && b, nil }
+	opts := WithAbsorbing(Options{}.WithWorkers(2).Commutative(true), func(x bool) bool { return !x })
+	result, err := ParallelReduce(input, andFunc, opts)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result {
+		t.Errorf("Expected false, got %v", result)
+	}
+}
+
+func TestParallelReduceK_Concat(t *testing.T) {
+	input := make([]string, 100)
+	expected := ""
+	for i := range input {
+		input[i] = fmt.Sprintf("%d,", i)
+		expected += input[i]
+	}
+	concat := func(group []string) (string, error) {
+		out := ""
+		for _, s := range group {
+			out += s
+		}
+		return out, nil
+	}
+
+	for _, fanIn := range []int{2, 3, 4, 7, 100, 1000} {
+		result, err := ParallelReduceK(input, fanIn, concat, Options{}.WithWorkers(3))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result != expected {
+			t.Errorf("FanIn %d: expected order to be preserved, got %q", fanIn, result)
+		}
+	}
+}
+
+func TestParallelReduceK_MergeSortedRuns(t *testing.T) {
+	input := [][]int{{1, 4, 9}, {2, 3, 10}, {0, 5}, {6, 7, 8}, {11}}
+	merge := func(runs [][]int) ([]int, error) {
+		var out []int
+		for _, run := range runs {
+			out = append(out, run...)
+		}
+		slices.Sort(out)
+		return out, nil
+	}
+
+	result, err := ParallelReduceK(input, 3, merge, Options{}.WithWorkers(2))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for i, x := range result {
+		if x != i {
+			t.Fatalf("Expected sorted run 0..11, got %v", result)
+		}
+	}
+}
+
+func TestParallelReduceK_InvalidFanIn(t *testing.T) {
+	sum := func(group []int) (int, error) { return 0, nil }
+	_, err := ParallelReduceK([]int{1, 2}, 1, sum, Options{})
+	if !errors.Is(err, ErrInvalidFanIn) {
+		t.Fatalf("Expected ErrInvalidFanIn, got %v", err)
+	}
+}
+
+func TestParallelReduceK_EmptyAndSingle(t *testing.T) {
+	sum := func(group []int) (int, error) {
+		total := 0
+		for _, x := range group {
+			total += x
+		}
+		return total, nil
+	}
+	result, err := ParallelReduceK([]int{}, 4, sum, Options{})
+	if err != nil || result != 0 {
+		t.Errorf("Expected 0, nil for empty input, got %d, %v", result, err)
+	}
+	result, err = ParallelReduceK([]int{42}, 4, sum, Options{})
+	if err != nil || result != 42 {
+		t.Errorf("Expected 42, nil for single element, got %d, %v", result, err)
+	}
+}
+
+func TestParallelReduceK_Error(t *testing.T) {
+	input := make([]int, 50)
+	fail := func(group []int) (int, error) { return 0, errors.New("fail") }
+	_, err := ParallelReduceK(input, 5, fail, Options{}.WithWorkers(2))
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+}
+
 func BenchmarkParallelReduce_HeavySum(b *testing.B) {
 	sizes := []int{1000, 10000, 100000, 1000000}
 
@@ -229,3 +414,121 @@ func BenchmarkParallelReduce_StringConcat(b *testing.B) {
 		}
 	}
 }
+
+func BenchmarkParallelReduce_Skewed(b *testing.B) {
+	input := make([]int, 256)
+	for i := range input {
+		input[i] = i + 1
+	}
+
+	// Every 16th combination is much slower than the rest
+	var calls atomic.Int64
+	skewedSum := func(a, b int) (int, error) {
+		if calls.Add(1)%16 == 0 {
+			time.Sleep(500 * time.Microsecond)
+		}
+		return a + b, nil
+	}
+
+	for _, commutative := range []bool{false, true} {
+		b.Run(fmt.Sprintf("Commutative%v", commutative), func(b *testing.B) {
+			opts := Options{}.WithWorkers(8).Commutative(commutative)
+			for b.Loop() {
+				_, err := ParallelReduce(input, skewedSum, opts)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestParallelReduce_ReduceErrorInputs(t *testing.T) {
+	input := []int{1, 2, 3, 4, 5, 6, 7, 8}
+	boom := errors.New("boom")
+	// Fails when combining 1+2 with 3+4, on the second level
+	failFunc := func(a, b int) (int, error) {
+		if a == 3 && b == 7 {
+			return 0, boom
+		}
+		return a + b, nil
+	}
+
+	_, err := ParallelReduce(input, failFunc, Options{}.WithWorkers(2))
+	var reduceErr *ReduceError
+	if !errors.As(err, &reduceErr) {
+		t.Fatalf("Expected *ReduceError, got %v", err)
+	}
+	if !errors.Is(err, boom) {
+		t.Errorf("Expected error to wrap the reduction error, got %v", err)
+	}
+	if reduceErr.Level != 1 {
+		t.Errorf("Expected failure at level 1, got %d", reduceErr.Level)
+	}
+	expected := [][]Range{{{Lo: 0, Hi: 2}}, {{Lo: 2, Hi: 4}}}
+	if fmt.Sprint(reduceErr.Operands) != fmt.Sprint(expected) {
+		t.Errorf("Expected operands %v, got %v", expected, reduceErr.Operands)
+	}
+}
+
+func TestParallelReduce_ReduceErrorModes(t *testing.T) {
+	// Only input 777 is large, so any value reaching the marker is a partial result covering it
+	const marker = 1 << 40
+	input := make([]int, 1000)
+	for i := range input {
+		input[i] = 1
+	}
+	input[777] = marker
+	failFunc := func(a, b int) (int, error) {
+		if a >= marker || b >= marker {
+			return 0, errors.New("fail on 777")
+		}
+		return a + b, nil
+	}
+
+	for name, opts := range map[string]Options{
+		"Deterministic": Options{}.WithWorkers(4).Deterministic(true),
+		"Commutative":   Options{}.WithWorkers(4).Commutative(true),
+	} {
+		_, err := ParallelReduce(input, failFunc, opts)
+		var reduceErr *ReduceError
+		if !errors.As(err, &reduceErr) {
+			t.Fatalf("%s: expected *ReduceError, got %v", name, err)
+		}
+		covered := false
+		for _, ranges := range reduceErr.Operands {
+			for _, r := range ranges {
+				covered = covered || (r.Lo <= 777 && 777 < r.Hi)
+			}
+		}
+		if !covered {
+			t.Errorf("%s: expected an operand covering input 777, got %v", name, reduceErr.Operands)
+		}
+	}
+}
+
+func TestParallelReduce_StopOnError(t *testing.T) {
+	input := make([]int, 100)
+	var calls atomic.Int64
+	failFunc := func(a, b int) (int, error) {
+		calls.Add(1)
+		return 0, errors.New("fail")
+	}
+
+	_, err := ParallelReduce(input, failFunc, Options{}.WithWorkers(1).StopOnError(true))
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Expected dispatch to stop after the first failure, made %d calls", calls.Load())
+	}
+
+	calls.Store(0)
+	_, err = ParallelReduce(input, failFunc, Options{}.WithWorkers(1))
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+	if calls.Load() != 50 {
+		t.Errorf("Expected the whole level to run without StopOnError, made %d calls", calls.Load())
+	}
+}
@@ -0,0 +1,117 @@
+package toil
+
+// ParallelScan returns the inclusive prefix "sums" of v under f: result[i] is v[0] combined with every item
+// up to and including v[i]. It uses the standard two-pass blocked algorithm: contiguous blocks are reduced
+// in parallel, the block results are scanned to find the prefix before each block, and every block is
+// then scanned in parallel from its prefix. As with ParallelReduce, f must be associative; it is called
+// roughly twice per item. If opts is Deterministic, blocks have a fixed size and the result does not
+// depend on the worker count. Errors from f are wrapped in a *ReduceError, and no results are returned.
+func ParallelScan[T any](v []T, f ReduceFunc[T], opts Options) ([]T, error) {
+	return parallelScan(v, f, nil, opts)
+}
+
+// ParallelScanExclusive returns the exclusive prefix "sums" of v under f, starting from init:
+// result[0] is init and result[i] is init combined with every item before v[i]. init should be the
+// identity of f, for example 0 for addition, to get the usual exclusive scan, such as the offsets at which
+// to write variable-length outputs. See ParallelScan.
+func ParallelScanExclusive[T any](v []T, f ReduceFunc[T], init T, opts Options) ([]T, error) {
+	return parallelScan(v, f, &init, opts)
+}
+
+// parallelScan computes an inclusive scan of v, or an exclusive one starting from *init if init is set.
+func parallelScan[T any](v []T, f ReduceFunc[T], init *T, opts Options) ([]T, error) {
+	if len(v) == 0 {
+		return []T{}, nil
+	}
+	opts = opts.resolve(len(v))
+	size := deterministicBlockSize
+	if !opts.deterministic {
+		size = opts.grain(len(v))
+	}
+
+	// combine folds v[i] into a running prefix covering [0, i), reporting failures against those inputs.
+	combine := func(prefix T, i int) (T, error) {
+		res, err := f(prefix, v[i])
+		if err != nil {
+			return res, &ReduceError{Level: 0, Operands: [][]Range{{{Lo: 0, Hi: i}}, {{Lo: i, Hi: i + 1}}}, Err: err}
+		}
+		return res, nil
+	}
+
+	// First pass: reduce every block but the last, which no other block depends on.
+	sums, _, err := foldBlocks(v[:(blockCount(len(v), size)-1)*size], size, opts, func(lo int, block []T) (T, bool, error) {
+		acc := block[0]
+		for i := 1; i < len(block); i++ {
+			var err error
+			if acc, err = f(acc, block[i]); err != nil {
+				return acc, false, &ReduceError{
+					Level:    0,
+					Operands: [][]Range{{{Lo: lo, Hi: lo + i}}, {{Lo: lo + i, Hi: lo + i + 1}}},
+					Err:      err,
+				}
+			}
+		}
+		return acc, false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The prefix before every block, scanned sequentially since there are only a few blocks per worker.
+	// hasPrefix is false for the first block of an inclusive scan, which has nothing before it.
+	prefixes := make([]T, len(sums)+1)
+	hasPrefix := init != nil
+	if hasPrefix {
+		prefixes[0] = *init
+	}
+	for b, sum := range sums {
+		if !hasPrefix {
+			prefixes[b+1] = sum
+			hasPrefix = true
+			continue
+		}
+		if prefixes[b+1], err = f(prefixes[b], sum); err != nil {
+			lo := b * size
+			return nil, &ReduceError{Level: 1, Operands: [][]Range{{{Lo: 0, Hi: lo}}, {{Lo: lo, Hi: lo + size}}}, Err: err}
+		}
+	}
+
+	// Second pass: scan every block from its prefix.
+	out := make([]T, len(v))
+	err = forEachBlock(fixedSplit(len(v), size), opts, func(_ int, b block) error {
+		if init != nil {
+			acc := prefixes[b.index]
+			out[b.lo] = acc
+			for i := b.lo; i+1 < b.hi; i++ {
+				var err error
+				if acc, err = combine(acc, i); err != nil {
+					return err
+				}
+				out[i+1] = acc
+			}
+			return nil
+		}
+
+		var acc T
+		start := b.lo
+		if b.index == 0 {
+			acc = v[0]
+			out[0] = acc
+			start++
+		} else {
+			acc = prefixes[b.index]
+		}
+		for i := start; i < b.hi; i++ {
+			var err error
+			if acc, err = combine(acc, i); err != nil {
+				return err
+			}
+			out[i] = acc
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,114 @@
+package toil
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func TestParallelScan(t *testing.T) {
+	sumFunc := func(a, b int) (int, error) { return a + b, nil }
+	for _, size := range []int{0, 1, 2, 17, 1000, 10001} {
+		input := make([]int, size)
+		expected := make([]int, size)
+		total := 0
+		for i := range input {
+			input[i] = i + 1
+			total += i + 1
+			expected[i] = total
+		}
+
+		for _, workers := range []int{1, 3, 8} {
+			result, err := ParallelScan(input, sumFunc, Options{}.WithWorkers(workers))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !slices.Equal(result, expected) {
+				t.Fatalf("Size %d, workers %d: expected %v, got %v", size, workers, expected, result)
+			}
+		}
+	}
+}
+
+func TestParallelScanExclusive_Offsets(t *testing.T) {
+	lengths := make([]int, 5000)
+	for i := range lengths {
+		lengths[i] = i % 13
+	}
+	sumFunc := func(a, b int) (int, error) { return a + b, nil }
+
+	offsets, err := ParallelScanExclusive(lengths, sumFunc, 0, Options{}.WithWorkers(4))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	offset := 0
+	for i, l := range lengths {
+		if offsets[i] != offset {
+			t.Fatalf("Expected offset %d at %d, got %d", offset, i, offsets[i])
+		}
+		offset += l
+	}
+}
+
+func TestParallelScan_PreservesOrder(t *testing.T) {
+	input := make([]string, 300)
+	for i := range input {
+		input[i] = strconv.Itoa(i % 10)
+	}
+	concat := func(a, b string) (string, error) { return a + b, nil }
+
+	result, err := ParallelScanExclusive(input, concat, ">", Options{}.WithWorkers(5))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	prefix := ">"
+	for i, s := range input {
+		if result[i] != prefix {
+			t.Fatalf("Expected %q at %d, got %q", prefix, i, result[i])
+		}
+		prefix += s
+	}
+}
+
+func TestParallelScan_Error(t *testing.T) {
+	input := make([]int, 1000)
+	for i := range input {
+		input[i] = i
+	}
+	failFunc := func(a, b int) (int, error) {
+		if b == 500 {
+			return 0, errors.New("fail on 500")
+		}
+		return a + b, nil
+	}
+
+	result, err := ParallelScan(input, failFunc, Options{}.WithWorkers(4))
+	var reduceErr *ReduceError
+	if !errors.As(err, &reduceErr) {
+		t.Fatalf("Expected *ReduceError, got %v", err)
+	}
+	if result != nil {
+		t.Errorf("Expected no results on error")
+	}
+}
+
+func TestParallelScan_DeterministicAcrossWorkers(t *testing.T) {
+	input := floatInput(20000)
+	sumFunc := func(a, b float64) (float64, error) { return a + b, nil }
+
+	expected, err := ParallelScan(input, sumFunc, Options{}.WithWorkers(1).Deterministic(true))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	result, err := ParallelScan(input, sumFunc, Options{}.WithWorkers(6).Deterministic(true))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for i := range expected {
+		if math.Float64bits(result[i]) != math.Float64bits(expected[i]) {
+			t.Fatalf("Expected bit-identical prefix at %d: %v != %v", i, result[i], expected[i])
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package toil
+
+// Schedule is the way items are handed out to workers, like the schedule clause of OpenMP loops.
+type Schedule int
+
+const (
+	// DynamicSchedule hands out chunks of the grain size from a shared queue, in order, to whichever worker
+	// is free. It balances uneven work well, at the cost of one atomic operation per chunk.
+	DynamicSchedule Schedule = iota
+	// StaticSchedule gives each worker one contiguous block of equal size up front, or deals chunks of the
+	// grain size round-robin if one is set. It has no scheduling cost and the best locality, but a worker
+	// that gets the expensive items finishes last while the others sit idle.
+	StaticSchedule
+	// GuidedSchedule hands out chunks from a shared queue like DynamicSchedule, but each chunk is the
+	// remaining work divided by the number of workers, so chunks start large and shrink towards the end,
+	// down to the grain size if one is set. It takes few chunks overall and still evens out the finish.
+	GuidedSchedule
+	// WorkStealingSchedule deals chunks of the grain size out to workers up front, like StaticSchedule, but
+	// into a deque per worker: a worker runs its own chunks in order and, once it runs out, steals the last
+	// chunks of another worker. Workers only contend when stealing, which suits many cores, and mostly run
+	// contiguous chunks, which suits caches.
+	WorkStealingSchedule
+)
+
+// String returns the name of the schedule.
+func (s Schedule) String() string {
+	switch s {
+	case DynamicSchedule:
+		return "dynamic"
+	case StaticSchedule:
+		return "static"
+	case GuidedSchedule:
+		return "guided"
+	case WorkStealingSchedule:
+		return "work-stealing"
+	}
+	return "unknown"
+}
+
+// split cuts a call over n items into blocks according to the schedule and grain size of o.
+// o.workers must be resolved.
+func (o Options) split(n int) split {
+	switch o.schedule {
+	case StaticSchedule:
+		size := o.grainSize
+		if size <= 0 {
+			size = max(1, (n+o.workers-1)/o.workers)
+		}
+		return split{n: n, size: size, schedule: StaticSchedule}
+	case GuidedSchedule:
+		bounds := []int{0}
+		for lo := 0; lo < n; {
+			lo = min(n, lo+max(1, o.grainSize, (n-lo+o.workers-1)/o.workers))
+			bounds = append(bounds, lo)
+		}
+		return split{n: n, bounds: bounds}
+	case WorkStealingSchedule:
+		return split{n: n, size: o.grain(n), schedule: WorkStealingSchedule}
+	}
+	return fixedSplit(n, o.grain(n))
+}
@@ -0,0 +1,226 @@
+package toil
+
+import (
+	"fmt"
+	"math"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var schedules = []Schedule{DynamicSchedule, StaticSchedule, GuidedSchedule, WorkStealingSchedule}
+
+func TestSchedule_CoversAllItems(t *testing.T) {
+	for _, schedule := range schedules {
+		for _, grain := range []int{0, 1, 5} {
+			for _, n := range []int{0, 1, 7, 100, 1001} {
+				opts := Options{}.WithWorkers(3).WithSchedule(schedule).WithGrainSize(grain)
+				seen := make([]atomic.Int32, n)
+				err := forEachBlock(opts.split(n), opts, func(worker int, b block) error {
+					if worker < 0 || worker >= 3 {
+						t.Errorf("Unexpected worker %d", worker)
+					}
+					for i := b.lo; i < b.hi; i++ {
+						seen[i].Add(1)
+					}
+					return nil
+				})
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				for i := range seen {
+					if seen[i].Load() != 1 {
+						t.Fatalf("%v, grain %d, n=%d: expected item %d to be visited once, got %d", schedule, grain, n, i, seen[i].Load())
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestSchedule_Static(t *testing.T) {
+	// Without a grain size, every worker gets one contiguous block
+	opts := Options{}.WithWorkers(4).WithSchedule(StaticSchedule)
+	s := opts.split(1000)
+	if s.count() != 4 {
+		t.Fatalf("Expected 4 blocks, got %d", s.count())
+	}
+
+	// With one, chunks are dealt round-robin, so worker w always runs the same chunks
+	opts = opts.WithGrainSize(10)
+	owner := make([]atomic.Int32, 100)
+	err := forEachBlock(opts.split(1000), opts, func(worker int, b block) error {
+		owner[b.index].Store(int32(worker))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for index := range owner {
+		if int(owner[index].Load()) != index%4 {
+			t.Errorf("Expected chunk %d to run on worker %d, got %d", index, index%4, owner[index].Load())
+		}
+	}
+}
+
+func TestSchedule_Guided(t *testing.T) {
+	opts := Options{}.WithWorkers(4).WithSchedule(GuidedSchedule)
+	s := opts.split(1000)
+	if first := s.at(0); first.hi-first.lo != 250 {
+		t.Errorf("Expected the first chunk to hold a quarter of the items, got %d", first.hi-first.lo)
+	}
+	for index := 1; index < s.count(); index++ {
+		prev, b := s.at(index-1), s.at(index)
+		if b.lo != prev.hi {
+			t.Fatalf("Chunk %d starts at %d, expected %d", index, b.lo, prev.hi)
+		}
+		if b.hi-b.lo > prev.hi-prev.lo {
+			t.Errorf("Chunk %d has %d items, more than the %d before it", index, b.hi-b.lo, prev.hi-prev.lo)
+		}
+	}
+
+	// The grain size bounds chunks from below
+	s = opts.WithGrainSize(16).split(1000)
+	for index := range s.count() - 1 {
+		if b := s.at(index); b.hi-b.lo < 16 {
+			t.Errorf("Chunk %d has %d items, fewer than the grain size", index, b.hi-b.lo)
+		}
+	}
+}
+
+func TestSchedule_WorkStealing(t *testing.T) {
+	// Worker 0 owns the first half of the chunks, which are slow: worker 1 must steal some of them
+	opts := Options{}.WithWorkers(2).WithSchedule(WorkStealingSchedule).WithGrainSize(1)
+	var owner [20]atomic.Int32
+	err := forEachBlock(opts.split(len(owner)), opts, func(worker int, b block) error {
+		if b.index < len(owner)/2 {
+			time.Sleep(time.Millisecond)
+		}
+		owner[b.index].Store(int32(worker))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	stolen := 0
+	for index := range len(owner) / 2 {
+		if owner[index].Load() == 1 {
+			stolen++
+		}
+	}
+	if stolen == 0 {
+		t.Error("Expected the idle worker to steal chunks")
+	}
+}
+
+func TestSchedule_FindFirst(t *testing.T) {
+	v := make([]int, 10000)
+	for i := range v {
+		v[i] = i % 1000
+	}
+	isTarget := func(x int) (bool, error) {
+		return x == 999, nil
+	}
+	for _, schedule := range schedules {
+		i, err := ParallelFindFirst(v, isTarget, Options{}.WithWorkers(4).WithSchedule(schedule).WithGrainSize(100))
+		if err != nil || i != 999 {
+			t.Errorf("%v: expected index 999, got %d and %v", schedule, i, err)
+		}
+	}
+}
+
+// spin does an amount of work proportional to units, which the compiler can't remove.
+func spin(units int) (float64, error) {
+	x := 0.0
+	for i := range units {
+		x += math.Sqrt(float64(i))
+	}
+	return x, nil
+}
+
+func BenchmarkSchedule(b *testing.B) {
+	const n = 1 << 14
+	workloads := []struct {
+		name string
+		cost func(i int) int
+	}{
+		// Every item costs the same
+		{"Uniform", func(int) int { return 64 }},
+		// Cost grows along the slice, like rows of a triangular matrix
+		{"Increasing", func(i int) int { return 1 + 128*i/n }},
+		// A few items at the end cost far more than the rest
+		{"SkewedTail", func(i int) int {
+			if i >= n-n/64 {
+				return 2048
+			}
+			return 16
+		}},
+	}
+	for _, workload := range workloads {
+		input := make([]int, n)
+		for i := range input {
+			input[i] = workload.cost(i)
+		}
+		for _, schedule := range schedules {
+			b.Run(fmt.Sprintf("%s/%v", workload.name, schedule), func(b *testing.B) {
+				opts := Options{}.WithWorkers(runtime.NumCPU()).WithSchedule(schedule)
+				for b.Loop() {
+					if _, err := ParallelTransform(input, spin, opts); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
+
+// channelTransform is the way ParallelTransform used to hand out work: every item goes through a
+// shared channel. It is kept as a baseline for the contention benchmarks.
+func channelTransform[I, O any](v []I, f TransformFunc[I, O], workers int) []O {
+	results := make([]O, len(v))
+	jobs := make(chan int, len(v))
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i], _ = f(v[i])
+			}
+		}()
+	}
+	for i := range v {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
+
+// BenchmarkSchedule_Contention hands out cheap items one at a time to many workers, so that the cost of
+// scheduling dominates.
+func BenchmarkSchedule_Contention(b *testing.B) {
+	input := make([]int, 1<<16)
+	for i := range input {
+		input[i] = 4
+	}
+	for _, workers := range []int{32, 64} {
+		b.Run(fmt.Sprintf("Workers%d/channel", workers), func(b *testing.B) {
+			for b.Loop() {
+				channelTransform(input, spin, workers)
+			}
+		})
+		for _, schedule := range []Schedule{DynamicSchedule, WorkStealingSchedule} {
+			b.Run(fmt.Sprintf("Workers%d/%v", workers, schedule), func(b *testing.B) {
+				opts := Options{}.WithWorkers(workers).WithSchedule(schedule).WithGrainSize(1)
+				for b.Loop() {
+					if _, err := ParallelTransform(input, spin, opts); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package toil
+
+import (
+	"sync/atomic"
+)
+
+// ParallelAny reports whether any item of v matches pred. It stops testing items as soon as one matches.
+// Errors are handled as in ParallelFilter: if opts has StopOnError set, the first error stops the search
+// and false is returned with it; otherwise failed items are treated as not matching, and the answer is
+// returned along with the first error.
+func ParallelAny[T any](v []T, pred PredicateFunc[T], opts Options) (bool, error) {
+	i, err := search(v, pred, true, false, opts)
+	return i >= 0, err
+}
+
+// ParallelAll reports whether every item of v matches pred, which is true for an empty slice.
+// It stops testing items as soon as one does not match. Errors are handled as in ParallelAny, failed items
+// being left out: if opts has StopOnError set, false is returned with the first error.
+func ParallelAll[T any](v []T, pred PredicateFunc[T], opts Options) (bool, error) {
+	i, err := search(v, pred, false, false, opts)
+	if err != nil && opts.stopOnError {
+		return false, err
+	}
+	return i < 0, err
+}
+
+// ParallelFind returns the index of any item of v matching pred, or -1 if there is none.
+// It stops testing items as soon as one matches, so which match is found depends on scheduling;
+// use ParallelFindFirst for the lowest index. Errors are handled as in ParallelAny, with -1 returned
+// if opts has StopOnError set.
+func ParallelFind[T any](v []T, pred PredicateFunc[T], opts Options) (int, error) {
+	return search(v, pred, true, false, opts)
+}
+
+// ParallelFindFirst returns the lowest index of an item of v matching pred, or -1 if there is none.
+// Once a match is found, only items at lower indices are still tested. Errors are handled as in
+// ParallelFind.
+func ParallelFindFirst[T any](v []T, pred PredicateFunc[T], opts Options) (int, error) {
+	return search(v, pred, true, true, opts)
+}
+
+// search returns the index of an item of v for which pred returns want, or -1. If first is set it returns
+// the lowest such index, otherwise whichever is found first.
+func search[T any](v []T, pred PredicateFunc[T], want bool, first bool, opts Options) (int, error) {
+	opts = opts.resolve(len(v))
+	n := int64(len(v))
+
+	var (
+		found  atomic.Int64 // Lowest index found so far, n if none
+		failed atomic.Bool
+	)
+	found.Store(n)
+
+	s := opts.split(len(v))
+	err := forEachBlock(s, opts, func(_ int, b block) error {
+		var blockErr error
+		for i := b.lo; i < b.hi; i++ {
+			if limit := found.Load(); (first && int64(i) >= limit) || (!first && limit < n) {
+				break
+			}
+			if opts.stopOnError && failed.Load() {
+				break
+			}
+
+			ok, err := pred(v[i])
+			if err != nil {
+				if opts.stopOnError {
+					failed.Store(true)
+					return err
+				}
+				if blockErr == nil {
+					blockErr = err
+				}
+				continue
+			}
+			if ok != want {
+				continue
+			}
+			for {
+				limit := found.Load()
+				if int64(i) >= limit || found.CompareAndSwap(limit, int64(i)) {
+					break
+				}
+			}
+			break
+		}
+
+		if blockErr != nil {
+			return blockErr
+		}
+		if found.Load() < n && (!first || s.ordered(opts)) {
+			// Blocks are started in order, so every block not started yet is past the match
+			return errBreak
+		}
+		return nil
+	})
+	if err != nil && opts.stopOnError {
+		return -1, err
+	}
+
+	if i := found.Load(); i < n {
+		return int(i), err
+	}
+	return -1, err
+}
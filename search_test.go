@@ -0,0 +1,118 @@
+package toil
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParallelAny(t *testing.T) {
+	input := make([]int, 1000)
+	for i := range input {
+		input[i] = i
+	}
+	opts := Options{}.WithWorkers(4)
+
+	found, err := ParallelAny(input, func(x int) (bool, error) { return x == 999, nil }, opts)
+	if err != nil || !found {
+		t.Errorf("Expected to find 999, got %v, %v", found, err)
+	}
+	found, err = ParallelAny(input, func(x int) (bool, error) { return x < 0, nil }, opts)
+	if err != nil || found {
+		t.Errorf("Expected no negative number, got %v, %v", found, err)
+	}
+}
+
+func TestParallelAll(t *testing.T) {
+	input := []int{2, 4, 6, 8, 10}
+	opts := Options{}.WithWorkers(2)
+
+	all, err := ParallelAll(input, isEven, opts)
+	if err != nil || !all {
+		t.Errorf("Expected all even, got %v, %v", all, err)
+	}
+	all, err = ParallelAll(append(input, 11), isEven, opts)
+	if err != nil || all {
+		t.Errorf("Expected not all even, got %v, %v", all, err)
+	}
+	all, err = ParallelAll([]int{}, isEven, opts)
+	if err != nil || !all {
+		t.Errorf("Expected true for empty input, got %v, %v", all, err)
+	}
+}
+
+func TestParallelAny_EarlyCancellation(t *testing.T) {
+	input := make([]int, 10000)
+	for i := range input {
+		input[i] = i
+	}
+	var calls atomic.Int64
+	isZero := func(x int) (bool, error) {
+		calls.Add(1)
+		time.Sleep(10 * time.Microsecond)
+		return x == 0, nil
+	}
+
+	found, err := ParallelAny(input, isZero, Options{}.WithWorkers(2))
+	if err != nil || !found {
+		t.Fatalf("Expected to find 0, got %v, %v", found, err)
+	}
+	if calls.Load() >= int64(len(input)) {
+		t.Errorf("Expected the search to stop early, made %d calls", calls.Load())
+	}
+}
+
+func TestParallelFind(t *testing.T) {
+	input := []string{"a", "b", "target", "c", "target"}
+	isTarget := func(s string) (bool, error) { return s == "target", nil }
+
+	i, err := ParallelFind(input, isTarget, Options{}.WithWorkers(3))
+	if err != nil || (i != 2 && i != 4) {
+		t.Errorf("Expected index 2 or 4, got %d, %v", i, err)
+	}
+	i, err = ParallelFind(input, func(s string) (bool, error) { return s == "z", nil }, Options{})
+	if err != nil || i != -1 {
+		t.Errorf("Expected -1, got %d, %v", i, err)
+	}
+}
+
+func TestParallelFindFirst(t *testing.T) {
+	input := make([]int, 10000)
+	for i := range input {
+		input[i] = i % 1000
+	}
+	// Later matches are much cheaper to test than the first one
+	slowFirst := func(x int) (bool, error) {
+		if x == 0 {
+			time.Sleep(time.Millisecond)
+		}
+		return x == 0 || x == 500, nil
+	}
+
+	for _, workers := range []int{1, 4, 16} {
+		i, err := ParallelFindFirst(input, slowFirst, Options{}.WithWorkers(workers))
+		if err != nil || i != 0 {
+			t.Errorf("Workers=%d: expected first match at 0, got %d, %v", workers, i, err)
+		}
+	}
+}
+
+func TestParallelFindFirst_Errors(t *testing.T) {
+	input := []int{1, 2, 3, 4}
+	failOnTwo := func(x int) (bool, error) {
+		if x == 2 {
+			return false, errors.New("fail on 2")
+		}
+		return x >= 2, nil
+	}
+
+	i, err := ParallelFindFirst(input, failOnTwo, Options{}.WithWorkers(1))
+	if err == nil || i != 2 {
+		t.Errorf("Expected index 2 and an error, got %d, %v", i, err)
+	}
+	i, err = ParallelFindFirst(input, failOnTwo, Options{}.WithWorkers(1).StopOnError(true))
+	if err == nil || i != -1 {
+		t.Errorf("Expected -1 and an error when stopping on error, got %d, %v", i, err)
+	}
+}
@@ -0,0 +1,130 @@
+package toil
+
+import (
+	"hash/maphash"
+	"iter"
+	"runtime"
+	"sync"
+)
+
+// ShardedMap is a map safe for concurrent use, split into independently locked shards by key hash so
+// that goroutines working on different keys rarely contend. It is meant to be shared by the functions
+// of a parallel call, for example to collect results by key from a TransformFunc.
+// The zero value is not usable; create one with NewShardedMap.
+type ShardedMap[K comparable, V any] struct {
+	seed   maphash.Seed
+	shards []mapShard[K, V]
+}
+
+type mapShard[K comparable, V any] struct {
+	mu sync.RWMutex
+	m  map[K]V
+}
+
+// NewShardedMap returns an empty ShardedMap with the given number of shards.
+// If shards is 0 or negative, four shards per CPU core are used.
+func NewShardedMap[K comparable, V any](shards int) *ShardedMap[K, V] {
+	if shards <= 0 {
+		shards = 4 * runtime.NumCPU()
+	}
+	m := &ShardedMap[K, V]{seed: maphash.MakeSeed(), shards: make([]mapShard[K, V], shards)}
+	for i := range m.shards {
+		m.shards[i].m = make(map[K]V)
+	}
+	return m
+}
+
+// shardOf returns the index of the shard holding key.
+func (m *ShardedMap[K, V]) shardOf(key K) int {
+	return int(maphash.Comparable(m.seed, key) % uint64(len(m.shards)))
+}
+
+// Load returns the value stored for key, and whether it was present.
+func (m *ShardedMap[K, V]) Load(key K) (V, bool) {
+	s := &m.shards[m.shardOf(key)]
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	v, ok := s.m[key]
+	return v, ok
+}
+
+// Store sets the value for key.
+func (m *ShardedMap[K, V]) Store(key K, value V) {
+	s := &m.shards[m.shardOf(key)]
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.m[key] = value
+}
+
+// LoadOrStore returns the value stored for key if present. Otherwise, it stores and returns value.
+// loaded reports whether the value was already present.
+func (m *ShardedMap[K, V]) LoadOrStore(key K, value V) (actual V, loaded bool) {
+	s := &m.shards[m.shardOf(key)]
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if v, ok := s.m[key]; ok {
+		return v, true
+	}
+	s.m[key] = value
+	return value, false
+}
+
+// Update atomically replaces the value for key with the result of f, which is given the current value
+// and whether it was present. It returns the new value. f runs with the key's shard locked, so it must
+// not call back into m.
+func (m *ShardedMap[K, V]) Update(key K, f func(old V, ok bool) V) V {
+	s := &m.shards[m.shardOf(key)]
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	old, ok := s.m[key]
+	v := f(old, ok)
+	s.m[key] = v
+	return v
+}
+
+// Delete removes key.
+func (m *ShardedMap[K, V]) Delete(key K) {
+	s := &m.shards[m.shardOf(key)]
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.m, key)
+}
+
+// Len returns the number of keys. Keys stored or deleted concurrently may or may not be counted.
+func (m *ShardedMap[K, V]) Len() int {
+	n := 0
+	for i := range m.shards {
+		s := &m.shards[i]
+		s.mu.RLock()
+		n += len(s.m)
+		s.mu.RUnlock()
+	}
+	return n
+}
+
+// All returns an iterator over the keys and values of m, one shard at a time. Each shard is read-locked
+// while it is being iterated over, so the loop body must not modify m.
+func (m *ShardedMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for i := range m.shards {
+			s := &m.shards[i]
+			s.mu.RLock()
+			for k, v := range s.m {
+				if !yield(k, v) {
+					s.mu.RUnlock()
+					return
+				}
+			}
+			s.mu.RUnlock()
+		}
+	}
+}
+
+// Map returns a copy of the contents of m as a plain map.
+func (m *ShardedMap[K, V]) Map() map[K]V {
+	out := make(map[K]V, m.Len())
+	for k, v := range m.All() {
+		out[k] = v
+	}
+	return out
+}
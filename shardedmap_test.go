@@ -0,0 +1,52 @@
+package toil
+
+import (
+	"testing"
+)
+
+func TestShardedMap_Basic(t *testing.T) {
+	m := NewShardedMap[string, int](4)
+	m.Store("a", 1)
+	if v, ok := m.Load("a"); !ok || v != 1 {
+		t.Errorf("Expected a=1, got %d, %v", v, ok)
+	}
+	if v, loaded := m.LoadOrStore("a", 2); !loaded || v != 1 {
+		t.Errorf("Expected LoadOrStore to load 1, got %d, %v", v, loaded)
+	}
+	if v, loaded := m.LoadOrStore("b", 2); loaded || v != 2 {
+		t.Errorf("Expected LoadOrStore to store 2, got %d, %v", v, loaded)
+	}
+	m.Delete("a")
+	if _, ok := m.Load("a"); ok {
+		t.Errorf("Expected a to be deleted")
+	}
+	if m.Len() != 1 {
+		t.Errorf("Expected 1 key, got %d", m.Len())
+	}
+}
+
+func TestShardedMap_ConcurrentUpdate(t *testing.T) {
+	m := NewShardedMap[int, int](0)
+	input := make([]int, 10000)
+	for i := range input {
+		input[i] = i
+	}
+
+	count := func(x int) (int, error) {
+		m.Update(x%10, func(old int, _ bool) int { return old + 1 })
+		return x, nil
+	}
+	if _, err := ParallelTransform(input, count, Options{}.WithWorkers(8)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	counts := m.Map()
+	if len(counts) != 10 {
+		t.Fatalf("Expected 10 keys, got %d", len(counts))
+	}
+	for k, v := range counts {
+		if v != 1000 {
+			t.Errorf("Expected key %d to be counted 1000 times, got %d", k, v)
+		}
+	}
+}
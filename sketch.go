@@ -0,0 +1,49 @@
+package toil
+
+import (
+	"errors"
+)
+
+// ErrSketchMismatch is returned when merging sketches built with different parameters.
+var ErrSketchMismatch = errors.New("toil: sketches have different parameters")
+
+const (
+	fnvOffset64 = 14695981039346656037
+	fnvPrime64  = 1099511628211
+)
+
+// HashBytes returns a well-mixed 64-bit hash of b for use with the sketches in this package.
+// It is stable across processes and machines, so sketches built in different places can be merged.
+func HashBytes(b []byte) uint64 {
+	h := uint64(fnvOffset64)
+	for _, c := range b {
+		h ^= uint64(c)
+		h *= fnvPrime64
+	}
+	return mix64(h)
+}
+
+// HashString is HashBytes for strings.
+func HashString(s string) uint64 {
+	h := uint64(fnvOffset64)
+	for i := 0; i < len(s); i++ {
+		h ^= uint64(s[i])
+		h *= fnvPrime64
+	}
+	return mix64(h)
+}
+
+// HashUint64 returns a well-mixed 64-bit hash of x, for integer keys.
+func HashUint64(x uint64) uint64 {
+	return mix64(x)
+}
+
+// mix64 is the splitmix64 finaliser, spreading every input bit over the whole output.
+func mix64(h uint64) uint64 {
+	h ^= h >> 30
+	h *= 0xbf58476d1ce4e5b9
+	h ^= h >> 27
+	h *= 0x94d049bb133111eb
+	h ^= h >> 31
+	return h
+}
@@ -0,0 +1,161 @@
+package toil
+
+import (
+	"cmp"
+	"slices"
+	"sort"
+)
+
+// sortCutoff is the length below which sorting and merging are done sequentially.
+const sortCutoff = 1 << 12
+
+// ParallelSort sorts x in increasing order, in place. See ParallelSortFunc.
+func ParallelSort[S ~[]E, E cmp.Ordered](x S, opts Options) {
+	parallelSort(x, cmp.Compare[E], slices.Sort[[]E], opts)
+}
+
+// ParallelSortFunc sorts x in increasing order as determined by cmp, in place, like slices.SortFunc.
+// x is split into one chunk per worker, the chunks are sorted concurrently and then merged pairwise,
+// each merge itself being split across workers. It needs a temporary buffer as large as x.
+// The sort is not guaranteed to be stable; use ParallelSortStableFunc to keep equal elements in order.
+func ParallelSortFunc[S ~[]E, E any](x S, cmp func(a, b E) int, opts Options) {
+	parallelSort(x, cmp, func(chunk []E) { slices.SortFunc(chunk, cmp) }, opts)
+}
+
+// ParallelSortStableFunc is ParallelSortFunc, keeping equal elements in their original order.
+func ParallelSortStableFunc[S ~[]E, E any](x S, cmp func(a, b E) int, opts Options) {
+	parallelSort(x, cmp, func(chunk []E) { slices.SortStableFunc(chunk, cmp) }, opts)
+}
+
+// ParallelMergeSorted merges runs, each already sorted by cmp, into a new sorted slice.
+// The merge is stable: equal elements keep the order of their runs, and their order within each run.
+func ParallelMergeSorted[E any](runs [][]E, cmp func(a, b E) int, opts Options) []E {
+	bounds := []int{0}
+	for _, run := range runs {
+		bounds = append(bounds, bounds[len(bounds)-1]+len(run))
+	}
+	opts = opts.resolve(bounds[len(bounds)-1])
+	src := concat(runs)
+	if len(runs) <= 1 || len(src) == 0 {
+		return src
+	}
+	return mergeRounds(src, make([]E, len(src)), bounds, cmp, opts)
+}
+
+// parallelSort sorts chunks of x with sortChunk and merges them with cmp. sortChunk must sort consistently
+// with cmp, and be stable for the whole sort to be stable.
+func parallelSort[E any](x []E, cmp func(a, b E) int, sortChunk func([]E), opts Options) {
+	opts = opts.resolve(len(x))
+	if len(x) <= sortCutoff || opts.workers == 1 {
+		sortChunk(x)
+		return
+	}
+
+	// Sort one chunk per worker
+	size := (len(x) + opts.workers - 1) / opts.workers
+	bounds := []int{0}
+	for lo := size; lo < len(x); lo += size {
+		bounds = append(bounds, lo)
+	}
+	bounds = append(bounds, len(x))
+	_ = forEachBlock(fixedSplit(len(x), size), opts, func(_ int, b block) error {
+		sortChunk(x[b.lo:b.hi])
+		return nil
+	})
+
+	sorted := mergeRounds(x, make([]E, len(x)), bounds, cmp, opts)
+	if &sorted[0] != &x[0] {
+		_ = forEachBlock(fixedSplit(len(x), size), opts, func(_ int, b block) error {
+			copy(x[b.lo:b.hi], sorted[b.lo:b.hi])
+			return nil
+		})
+	}
+}
+
+// a mergeTask merges a[...] and b[...] into dst, which is exactly as long as both.
+type mergeTask[E any] struct {
+	a, b, dst []E
+}
+
+// mergeRounds merges the consecutive sorted runs of src delimited by bounds (starting at 0 and ending at
+// len(src)) pairwise, alternating between src and buf, until one run remains. It returns whichever of
+// src and buf holds the result. Merges are split into pieces so that every round keeps all workers busy.
+func mergeRounds[E any](src, buf []E, bounds []int, cmp func(a, b E) int, opts Options) []E {
+	for len(bounds) > 2 {
+		var tasks []mergeTask[E]
+		next := []int{0}
+		for i := 0; i+1 < len(bounds); i += 2 {
+			if i+2 == len(bounds) {
+				// Odd run out, carried over as is
+				tasks = append(tasks, mergeTask[E]{a: src[bounds[i]:bounds[i+1]], dst: buf[bounds[i]:bounds[i+1]]})
+				next = append(next, bounds[i+1])
+				break
+			}
+			a := src[bounds[i]:bounds[i+1]]
+			b := src[bounds[i+1]:bounds[i+2]]
+			dst := buf[bounds[i]:bounds[i+2]]
+			pieces := max(1, 2*opts.workers*len(dst)/len(src))
+			tasks = append(tasks, splitMerge(a, b, dst, pieces, cmp)...)
+			next = append(next, bounds[i+2])
+		}
+
+		_ = forEachBlock(fixedSplit(len(tasks), 1), opts, func(_ int, blk block) error {
+			t := tasks[blk.index]
+			mergeInto(t.a, t.b, t.dst, cmp)
+			return nil
+		})
+
+		src, buf = buf, src
+		bounds = next
+	}
+	return src
+}
+
+// splitMerge splits the merge of sorted runs a and b into dst into up to pieces independent merges.
+// The longer run is cut at evenly spaced pivots and the other at the matching positions, so that every
+// piece only holds elements ordering before the next piece, and ties still favour a.
+func splitMerge[E any](a, b, dst []E, pieces int, cmp func(a, b E) int) []mergeTask[E] {
+	if pieces <= 1 || len(dst) <= sortCutoff {
+		return []mergeTask[E]{{a: a, b: b, dst: dst}}
+	}
+
+	var tasks []mergeTask[E]
+	ai, bi := 0, 0
+	for p := 1; p <= pieces; p++ {
+		var aj, bj int
+		switch {
+		case p == pieces:
+			aj, bj = len(a), len(b)
+		case len(a) >= len(b):
+			// Elements of b before the pivot a[aj] are the ones strictly less than it
+			aj = p * len(a) / pieces
+			bj = bi + sort.Search(len(b)-bi, func(k int) bool { return cmp(b[bi+k], a[aj]) >= 0 })
+		default:
+			// Elements of a before the pivot b[bj] are the ones less than or equal to it
+			bj = p * len(b) / pieces
+			aj = ai + sort.Search(len(a)-ai, func(k int) bool { return cmp(a[ai+k], b[bj]) > 0 })
+		}
+		aj, bj = max(aj, ai), max(bj, bi)
+		lo := ai + bi
+		tasks = append(tasks, mergeTask[E]{a: a[ai:aj], b: b[bi:bj], dst: dst[lo : aj+bj]})
+		ai, bi = aj, bj
+	}
+	return tasks
+}
+
+// mergeInto merges sorted runs a and b into dst, taking from a first on ties.
+func mergeInto[E any](a, b, dst []E, cmp func(a, b E) int) {
+	i, j, k := 0, 0, 0
+	for i < len(a) && j < len(b) {
+		if cmp(a[i], b[j]) <= 0 {
+			dst[k] = a[i]
+			i++
+		} else {
+			dst[k] = b[j]
+			j++
+		}
+		k++
+	}
+	k += copy(dst[k:], a[i:])
+	copy(dst[k:], b[j:])
+}
@@ -0,0 +1,121 @@
+package toil
+
+import (
+	"cmp"
+	"fmt"
+	"math/rand/v2"
+	"runtime"
+	"slices"
+	"testing"
+)
+
+func TestParallelSort(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	for _, size := range []int{0, 1, 100, sortCutoff + 1, 100000} {
+		input := make([]int, size)
+		for i := range input {
+			input[i] = r.IntN(1000)
+		}
+		expected := slices.Clone(input)
+		slices.Sort(expected)
+
+		for _, workers := range []int{1, 3, 8} {
+			x := slices.Clone(input)
+			ParallelSort(x, Options{}.WithWorkers(workers))
+			if !slices.Equal(x, expected) {
+				t.Fatalf("Size %d, workers %d: result is not sorted", size, workers)
+			}
+		}
+	}
+}
+
+func TestParallelSortStableFunc(t *testing.T) {
+	type record struct {
+		key, seq int
+	}
+	r := rand.New(rand.NewPCG(3, 4))
+	input := make([]record, 200000)
+	for i := range input {
+		input[i] = record{key: r.IntN(100), seq: i}
+	}
+
+	byKey := func(a, b record) int { return cmp.Compare(a.key, b.key) }
+	ParallelSortStableFunc(input, byKey, Options{}.WithWorkers(7))
+
+	for i := 1; i < len(input); i++ {
+		a, b := input[i-1], input[i]
+		if a.key > b.key || (a.key == b.key && a.seq > b.seq) {
+			t.Fatalf("Expected a stable sort, found %v before %v", a, b)
+		}
+	}
+}
+
+func TestParallelSortFunc_Descending(t *testing.T) {
+	input := make([]string, 20000)
+	for i := range input {
+		input[i] = fmt.Sprintf("%05d", (i*7919)%len(input))
+	}
+	ParallelSortFunc(input, func(a, b string) int { return cmp.Compare(b, a) }, Options{}.WithWorkers(4))
+	if !slices.IsSortedFunc(input, func(a, b string) int { return cmp.Compare(b, a) }) {
+		t.Errorf("Expected descending order")
+	}
+}
+
+func TestParallelMergeSorted(t *testing.T) {
+	type record struct {
+		key, run int
+	}
+	var runs [][]record
+	var expected []record
+	for run := 0; run < 5; run++ {
+		var r []record
+		for key := 0; key < 10000; key += run + 1 {
+			r = append(r, record{key: key, run: run})
+		}
+		runs = append(runs, r)
+		expected = append(expected, r...)
+	}
+	byKey := func(a, b record) int { return cmp.Compare(a.key, b.key) }
+	slices.SortStableFunc(expected, byKey)
+
+	result := ParallelMergeSorted(runs, byKey, Options{}.WithWorkers(4))
+	if !slices.Equal(result, expected) {
+		t.Errorf("Expected a stable merge of the runs")
+	}
+}
+
+func TestParallelMergeSorted_Empty(t *testing.T) {
+	result := ParallelMergeSorted([][]int{{}, nil, {1, 3}, {2}}, cmp.Compare[int], Options{})
+	if !slices.Equal(result, []int{1, 2, 3}) {
+		t.Errorf("Expected [1 2 3], got %v", result)
+	}
+
+	// Runs with no elements at all
+	result = ParallelMergeSorted([][]int{{}, {}}, cmp.Compare[int], Options{})
+	if len(result) != 0 {
+		t.Errorf("Expected no elements, got %v", result)
+	}
+}
+
+func BenchmarkParallelSort(b *testing.B) {
+	r := rand.New(rand.NewPCG(5, 6))
+	input := make([]int, 1000000)
+	for i := range input {
+		input[i] = r.Int()
+	}
+	x := make([]int, len(input))
+
+	b.Run("slices.Sort", func(b *testing.B) {
+		for b.Loop() {
+			copy(x, input)
+			slices.Sort(x)
+		}
+	})
+	b.Run("ParallelSort", func(b *testing.B) {
+		opts := Options{}.WithWorkers(runtime.NumCPU())
+		for b.Loop() {
+			copy(x, input)
+			ParallelSort(x, opts)
+		}
+	})
+}
@@ -0,0 +1,203 @@
+package toil
+
+import (
+	"cmp"
+	"errors"
+	"math"
+	"slices"
+)
+
+// Number is the set of numeric types the built-in aggregators work with.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// ErrHistogramBounds is returned when merging histograms with different bucket bounds.
+var ErrHistogramBounds = errors.New("toil: histograms have different bounds")
+
+// Sum is a ReduceFunc adding two numbers.
+func Sum[T Number](a, b T) (T, error) {
+	return a + b, nil
+}
+
+// Min is a ReduceFunc returning the smaller of two values.
+func Min[T cmp.Ordered](a, b T) (T, error) {
+	return min(a, b), nil
+}
+
+// Max is a ReduceFunc returning the larger of two values.
+func Max[T cmp.Ordered](a, b T) (T, error) {
+	return max(a, b), nil
+}
+
+// SumOf is an Aggregator summing numbers.
+func SumOf[T Number]() Aggregator[T, T] {
+	return Aggregator[T, T]{
+		Zero:  func() T { return 0 },
+		Add:   func(acc T, _ int, x T) (T, error) { return acc + x, nil },
+		Merge: Sum[T],
+	}
+}
+
+// CountOf is an Aggregator counting items.
+func CountOf[T any]() Aggregator[T, int] {
+	return Aggregator[T, int]{
+		Zero:  func() int { return 0 },
+		Add:   func(acc int, _ int, _ T) (int, error) { return acc + 1, nil },
+		Merge: Sum[int],
+	}
+}
+
+// Arg is an extreme value along with its index in the input. Ok is false if no value has been seen.
+type Arg[T any] struct {
+	Index int
+	Value T
+	Ok    bool
+}
+
+// ArgMin is a ReduceFunc keeping the smaller of two Args, or the one with the lower index on ties.
+func ArgMin[T cmp.Ordered](a, b Arg[T]) (Arg[T], error) {
+	if !b.Ok || (a.Ok && (a.Value < b.Value || (a.Value == b.Value && a.Index < b.Index))) {
+		return a, nil
+	}
+	return b, nil
+}
+
+// ArgMax is a ReduceFunc keeping the larger of two Args, or the one with the lower index on ties.
+func ArgMax[T cmp.Ordered](a, b Arg[T]) (Arg[T], error) {
+	if !b.Ok || (a.Ok && (a.Value > b.Value || (a.Value == b.Value && a.Index < b.Index))) {
+		return a, nil
+	}
+	return b, nil
+}
+
+// ArgMinOf is an Aggregator finding the smallest value and the index of its first occurrence.
+func ArgMinOf[T cmp.Ordered]() Aggregator[T, Arg[T]] {
+	return Aggregator[T, Arg[T]]{
+		Zero: func() Arg[T] { return Arg[T]{} },
+		Add: func(acc Arg[T], i int, x T) (Arg[T], error) {
+			return ArgMin(acc, Arg[T]{Index: i, Value: x, Ok: true})
+		},
+		Merge: ArgMin[T],
+	}
+}
+
+// ArgMaxOf is an Aggregator finding the largest value and the index of its first occurrence.
+func ArgMaxOf[T cmp.Ordered]() Aggregator[T, Arg[T]] {
+	return Aggregator[T, Arg[T]]{
+		Zero: func() Arg[T] { return Arg[T]{} },
+		Add: func(acc Arg[T], i int, x T) (Arg[T], error) {
+			return ArgMax(acc, Arg[T]{Index: i, Value: x, Ok: true})
+		},
+		Merge: ArgMax[T],
+	}
+}
+
+// Moments holds the count, mean and sum of squared deviations of a set of numbers, updated with
+// Welford's algorithm so the variance stays accurate even when the mean is large.
+type Moments struct {
+	Count int64
+	Mean  float64
+	M2    float64
+}
+
+// Add returns m with x added to it.
+func (m Moments) Add(x float64) Moments {
+	m.Count++
+	delta := x - m.Mean
+	m.Mean += delta / float64(m.Count)
+	m.M2 += delta * (x - m.Mean)
+	return m
+}
+
+// Variance returns the population variance, or NaN if m is empty.
+func (m Moments) Variance() float64 {
+	if m.Count == 0 {
+		return math.NaN()
+	}
+	return m.M2 / float64(m.Count)
+}
+
+// SampleVariance returns the unbiased sample variance, or NaN if m has fewer than two values.
+func (m Moments) SampleVariance() float64 {
+	if m.Count < 2 {
+		return math.NaN()
+	}
+	return m.M2 / float64(m.Count-1)
+}
+
+// MergeMoments is a ReduceFunc combining the moments of two sets of numbers (Chan et al.).
+func MergeMoments(a, b Moments) (Moments, error) {
+	if a.Count == 0 {
+		return b, nil
+	}
+	if b.Count == 0 {
+		return a, nil
+	}
+	count := a.Count + b.Count
+	delta := b.Mean - a.Mean
+	return Moments{
+		Count: count,
+		Mean:  a.Mean + delta*float64(b.Count)/float64(count),
+		M2:    a.M2 + b.M2 + delta*delta*float64(a.Count)*float64(b.Count)/float64(count),
+	}, nil
+}
+
+// MomentsOf is an Aggregator computing the mean and variance of numbers.
+func MomentsOf[T Number]() Aggregator[T, Moments] {
+	return Aggregator[T, Moments]{
+		Zero:  func() Moments { return Moments{} },
+		Add:   func(acc Moments, _ int, x T) (Moments, error) { return acc.Add(float64(x)), nil },
+		Merge: MergeMoments,
+	}
+}
+
+// Histogram counts numbers in fixed buckets. With n Bounds there are n+1 Counts: Counts[i] is the number
+// of values v with Bounds[i-1] <= v < Bounds[i], the first bucket being unbounded below and the last
+// unbounded above. Bounds must be sorted in increasing order.
+type Histogram[T Number] struct {
+	Bounds []T
+	Counts []int64
+}
+
+// NewHistogram returns an empty histogram with the given bucket bounds.
+func NewHistogram[T Number](bounds ...T) Histogram[T] {
+	return Histogram[T]{Bounds: bounds, Counts: make([]int64, len(bounds)+1)}
+}
+
+// Add counts x in h. The counts are updated in place.
+func (h Histogram[T]) Add(x T) Histogram[T] {
+	i, found := slices.BinarySearch(h.Bounds, x)
+	if found {
+		// x is the lower bound of the next bucket; skip over duplicated bounds
+		for i < len(h.Bounds) && h.Bounds[i] == x {
+			i++
+		}
+	}
+	h.Counts[i]++
+	return h
+}
+
+// MergeHistograms is a ReduceFunc adding the counts of two histograms with the same bounds.
+// The result has its own counts, so neither input is modified.
+func MergeHistograms[T Number](a, b Histogram[T]) (Histogram[T], error) {
+	if !slices.Equal(a.Bounds, b.Bounds) {
+		return Histogram[T]{}, ErrHistogramBounds
+	}
+	counts := make([]int64, len(a.Counts))
+	for i := range counts {
+		counts[i] = a.Counts[i] + b.Counts[i]
+	}
+	return Histogram[T]{Bounds: a.Bounds, Counts: counts}, nil
+}
+
+// HistogramOf is an Aggregator counting numbers in the buckets delimited by bounds; see Histogram.
+func HistogramOf[T Number](bounds ...T) Aggregator[T, Histogram[T]] {
+	return Aggregator[T, Histogram[T]]{
+		Zero:  func() Histogram[T] { return NewHistogram(bounds...) },
+		Add:   func(acc Histogram[T], _ int, x T) (Histogram[T], error) { return acc.Add(x), nil },
+		Merge: MergeHistograms[T],
+	}
+}
@@ -0,0 +1,108 @@
+package toil
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestStats_MinMax(t *testing.T) {
+	input := []int{5, 3, 9, -2, 7}
+	lo, err := ParallelReduce(input, Min[int], Options{}.WithWorkers(2))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	hi, err := ParallelReduce(input, Max[int], Options{}.WithWorkers(2))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if lo != -2 || hi != 9 {
+		t.Errorf("Expected min -2 and max 9, got %d and %d", lo, hi)
+	}
+}
+
+func TestStats_Count(t *testing.T) {
+	input := make([]string, 1234)
+	result, err := ParallelAggregate(input, CountOf[string](), Options{}.WithWorkers(3))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result != 1234 {
+		t.Errorf("Expected 1234, got %d", result)
+	}
+}
+
+func TestStats_ArgMinArgMax(t *testing.T) {
+	input := []float64{3, 1, 4, 1, 5, 9, 2, 6, 5, 3, 5, 9}
+	lo, err := ParallelAggregate(input, ArgMinOf[float64](), Options{}.WithWorkers(3))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !lo.Ok || lo.Index != 1 || lo.Value != 1 {
+		t.Errorf("Expected first minimum at index 1, got %+v", lo)
+	}
+	hi, err := ParallelAggregate(input, ArgMaxOf[float64](), Options{}.WithWorkers(3))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !hi.Ok || hi.Index != 5 || hi.Value != 9 {
+		t.Errorf("Expected first maximum at index 5, got %+v", hi)
+	}
+
+	empty, err := ParallelAggregate([]float64{}, ArgMinOf[float64](), Options{})
+	if err != nil || empty.Ok {
+		t.Errorf("Expected no minimum for empty input, got %+v, %v", empty, err)
+	}
+}
+
+func TestStats_Moments(t *testing.T) {
+	input := make([]int32, 9999)
+	var sum float64
+	for i := range input {
+		input[i] = int32(1e6 + i%101)
+		sum += float64(input[i])
+	}
+	mean := sum / float64(len(input))
+	var sq float64
+	for _, x := range input {
+		sq += (float64(x) - mean) * (float64(x) - mean)
+	}
+
+	result, err := ParallelAggregate(input, MomentsOf[int32](), Options{}.WithWorkers(4))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.Count != int64(len(input)) {
+		t.Errorf("Expected count %d, got %d", len(input), result.Count)
+	}
+	if math.Abs(result.Mean-mean) > 1e-6 {
+		t.Errorf("Expected mean %v, got %v", mean, result.Mean)
+	}
+	if math.Abs(result.Variance()-sq/float64(len(input))) > 1e-6 {
+		t.Errorf("Expected variance %v, got %v", sq/float64(len(input)), result.Variance())
+	}
+	if !math.IsNaN((Moments{}).Variance()) {
+		t.Errorf("Expected NaN variance for empty moments")
+	}
+}
+
+func TestStats_Histogram(t *testing.T) {
+	input := []int{-5, 0, 1, 9, 10, 11, 99, 100, 1000}
+	result, err := ParallelAggregate(input, HistogramOf(0, 10, 100), Options{}.WithWorkers(2))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []int64{1, 3, 3, 2}
+	for i, count := range result.Counts {
+		if count != expected[i] {
+			t.Errorf("Expected bucket %d to have %d values, got %d", i, expected[i], count)
+		}
+	}
+}
+
+func TestStats_HistogramBoundsMismatch(t *testing.T) {
+	_, err := MergeHistograms(NewHistogram(1, 2), NewHistogram(1, 3))
+	if !errors.Is(err, ErrHistogramBounds) {
+		t.Fatalf("Expected ErrHistogramBounds, got %v", err)
+	}
+}
@@ -0,0 +1,69 @@
+package toil
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// blockQueue holds the blocks [lo, hi) a worker still has to run under WorkStealingSchedule. The owner
+// takes blocks from the bottom, in order, so it works through contiguous items; thieves take the top half,
+// the blocks furthest from the owner's. A mutex is enough: the owner is the only one locking it until other
+// workers run dry and start stealing, and each steal takes half the work, so steals are rare.
+type blockQueue struct {
+	mu     sync.Mutex
+	lo, hi int
+	size   atomic.Int64 // hi - lo, so that thieves can skip empty queues without locking them
+}
+
+// set replaces the blocks of q with [lo, hi).
+func (q *blockQueue) set(lo, hi int) {
+	q.mu.Lock()
+	q.lo, q.hi = lo, hi
+	q.size.Store(int64(hi - lo))
+	q.mu.Unlock()
+}
+
+// pop takes the lowest block of q.
+func (q *blockQueue) pop() (index int, ok bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.lo == q.hi {
+		return 0, false
+	}
+	index = q.lo
+	q.lo++
+	q.size.Add(-1)
+	return index, true
+}
+
+// steal takes the upper half of the blocks of q, at least one.
+func (q *blockQueue) steal() (lo, hi int, ok bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.lo == q.hi {
+		return 0, 0, false
+	}
+	lo, hi = q.hi-(q.hi-q.lo+1)/2, q.hi
+	q.hi = lo
+	q.size.Store(int64(q.hi - q.lo))
+	return lo, hi, true
+}
+
+// claim returns the next block for worker self to run: its own lowest block, or else the first block
+// of half the work of another worker, whose other blocks become its own.
+func claim(queues []blockQueue, self int) (index int, ok bool) {
+	if index, ok = queues[self].pop(); ok {
+		return index, true
+	}
+	for i := 1; i < len(queues); i++ {
+		victim := &queues[(self+i)%len(queues)]
+		if victim.size.Load() == 0 {
+			continue
+		}
+		if lo, hi, ok := victim.steal(); ok {
+			queues[self].set(lo+1, hi)
+			return lo, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,64 @@
+package toil
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestBlockQueue_PopAndSteal(t *testing.T) {
+	var q blockQueue
+	q.set(0, 5)
+
+	// The owner takes the lowest block, thieves the upper half
+	if index, ok := q.pop(); !ok || index != 0 {
+		t.Errorf("Expected pop to return 0, got %d, %v", index, ok)
+	}
+	if lo, hi, ok := q.steal(); !ok || lo != 3 || hi != 5 {
+		t.Errorf("Expected steal to return [3, 5), got [%d, %d), %v", lo, hi, ok)
+	}
+	if lo, hi, ok := q.steal(); !ok || lo != 2 || hi != 3 {
+		t.Errorf("Expected steal to return [2, 3), got [%d, %d), %v", lo, hi, ok)
+	}
+	if index, ok := q.pop(); !ok || index != 1 {
+		t.Errorf("Expected pop to return 1, got %d, %v", index, ok)
+	}
+	if _, ok := q.pop(); ok {
+		t.Error("Expected pop on an empty queue to fail")
+	}
+	if _, _, ok := q.steal(); ok {
+		t.Error("Expected steal on an empty queue to fail")
+	}
+	if q.size.Load() != 0 {
+		t.Errorf("Expected size 0, got %d", q.size.Load())
+	}
+}
+
+func TestClaim_Concurrent(t *testing.T) {
+	const n = 10000
+	// All the work starts with one worker, the others have to steal it
+	queues := make([]blockQueue, 4)
+	queues[0].set(0, n)
+
+	seen := make([]int, n)
+	var wg sync.WaitGroup
+	for w := range queues {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				index, ok := claim(queues, w)
+				if !ok {
+					return
+				}
+				seen[index]++
+			}
+		}()
+	}
+	wg.Wait()
+
+	for index, count := range seen {
+		if count != 1 {
+			t.Fatalf("Expected block %d to be claimed once, got %d", index, count)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package toil
+
+import (
+	"errors"
+	"iter"
+	"math/bits"
+)
+
+// streamChunkSize is the number of items ReduceSeq buffers before reducing them in parallel.
+// It is fixed so that the shape of a streamed reduction never depends on the worker count.
+const streamChunkSize = 16 * deterministicBlockSize
+
+// a partial is the result of reducing 2^size consecutive chunks of a stream, covering the inputs in covers.
+type partial[T any] struct {
+	value  T
+	size   int
+	covers Range
+	depth  int // Level of the call that produced value, see ReduceError
+}
+
+// ReduceSeq reduces the values produced by seq to a single value, as they arrive. Values are buffered
+// in fixed-size chunks which are reduced in parallel with ParallelReduce, and chunk results are combined
+// like a binary counter, so memory use is bounded by one chunk plus a logarithmic number of partial results.
+// As with ParallelReduce, f should be associative; values are always combined in the order seq yields them,
+// so Commutative is ignored.
+// Reduction stops consuming seq as soon as an error occurs or an absorbing result is found.
+// If the sequence is empty, the zero value of T is returned.
+func ReduceSeq[T any](seq iter.Seq[T], f ReduceFunc[T], opts Options) (T, error) {
+	var zero T
+	absorbing, err := optionFunc[func(T) bool](opts.absorbing)
+	if err != nil {
+		return zero, err
+	}
+	// Chunks are combined in order anyway, so keep the order within them too
+	opts.commutative = false
+
+	var (
+		stack    []partial[T]
+		chunk    = make([]T, 0, streamChunkSize)
+		consumed int // Number of values taken from seq so far
+	)
+
+	// push adds the result of a chunk to the stack, combining equally sized neighbours.
+	// It reports whether the reduction is already decided.
+	push := func(p partial[T]) (bool, error) {
+		if absorbing != nil && absorbing(p.value) {
+			stack = append(stack[:0], p)
+			return true, nil
+		}
+		for len(stack) > 0 && stack[len(stack)-1].size == p.size {
+			left := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			combined, err := combinePartials(f, left, p)
+			if err != nil {
+				return false, err
+			}
+			if absorbing != nil && absorbing(combined.value) {
+				stack = append(stack[:0], combined)
+				return true, nil
+			}
+			p = combined
+		}
+		stack = append(stack, p)
+		return false, nil
+	}
+
+	// flush reduces the buffered chunk and pushes its result.
+	flush := func() (bool, error) {
+		lo := consumed - len(chunk)
+		value, err := ParallelReduce(chunk, f, opts)
+		if err != nil {
+			// Report input ranges relative to the whole stream rather than the chunk
+			var reduceErr *ReduceError
+			if errors.As(err, &reduceErr) {
+				for _, ranges := range reduceErr.Operands {
+					for i := range ranges {
+						ranges[i].Lo += lo
+						ranges[i].Hi += lo
+					}
+				}
+			}
+			return false, err
+		}
+		depth := bits.Len(uint(len(chunk)-1)) - 1 // Level of the last call of a binary tree over the chunk
+		chunk = chunk[:0]
+		return push(partial[T]{value: value, covers: Range{Lo: lo, Hi: consumed}, depth: depth})
+	}
+
+	for x := range seq {
+		consumed++
+		chunk = append(chunk, x)
+		if len(chunk) < streamChunkSize {
+			continue
+		}
+		decided, err := flush()
+		if err != nil {
+			return zero, err
+		}
+		if decided {
+			return stack[0].value, nil
+		}
+	}
+	if len(chunk) > 0 {
+		decided, err := flush()
+		if err != nil {
+			return zero, err
+		}
+		if decided {
+			return stack[0].value, nil
+		}
+	}
+	if len(stack) == 0 {
+		return zero, nil
+	}
+
+	// Combine the remaining partials from the right, keeping earlier values on the left.
+	acc := stack[len(stack)-1]
+	for i := len(stack) - 2; i >= 0; i-- {
+		if acc, err = combinePartials(f, stack[i], acc); err != nil {
+			return zero, err
+		}
+		if absorbing != nil && absorbing(acc.value) {
+			break
+		}
+	}
+	return acc.value, nil
+}
+
+// combinePartials combines two adjacent partial results of a stream, reporting failures as a *ReduceError.
+func combinePartials[T any](f ReduceFunc[T], left, right partial[T]) (partial[T], error) {
+	depth := max(left.depth, right.depth) + 1
+	value, err := f(left.value, right.value)
+	if err != nil {
+		return partial[T]{}, &ReduceError{Level: depth, Operands: [][]Range{{left.covers}, {right.covers}}, Err: err}
+	}
+	return partial[T]{
+		value:  value,
+		size:   left.size + 1,
+		covers: Range{Lo: left.covers.Lo, Hi: right.covers.Hi},
+		depth:  depth,
+	}, nil
+}
+
+// ReduceChan is like ReduceSeq, reducing the values received from ch until it is closed.
+// If the reduction finishes early because of an error or an absorbing result, ch is not drained;
+// stopping its producers is left to the caller.
+func ReduceChan[T any](ch <-chan T, f ReduceFunc[T], opts Options) (T, error) {
+	seq := func(yield func(T) bool) {
+		for x := range ch {
+			if !yield(x) {
+				return
+			}
+		}
+	}
+	return ReduceSeq(seq, f, opts)
+}
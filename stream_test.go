@@ -0,0 +1,170 @@
+package toil
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func TestReduceSeq_Sum(t *testing.T) {
+	sumFunc := func(a, b int) (int, error) { return a + b, nil }
+	for _, size := range []int{0, 1, 2, streamChunkSize - 1, streamChunkSize, 3*streamChunkSize + 5} {
+		input := make([]int, size)
+		expected := 0
+		for i := range input {
+			input[i] = i
+			expected += i
+		}
+		result, err := ReduceSeq(slices.Values(input), sumFunc, Options{}.WithWorkers(4))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result != expected {
+			t.Errorf("Size %d: expected %d, got %d", size, expected, result)
+		}
+	}
+}
+
+func TestReduceSeq_PreservesOrder(t *testing.T) {
+	// Concatenating digit sequences is associative but not commutative
+	input := make([]string, 5*streamChunkSize+17)
+	for i := range input {
+		input[i] = strconv.Itoa(i % 10)
+	}
+	concat := func(a, b string) (string, error) { return a + b, nil }
+
+	for _, opts := range []Options{Options{}.WithWorkers(3), Options{}.WithWorkers(8).Commutative(true)} {
+		result, err := ReduceSeq(slices.Values(input), concat, opts)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		for i, s := range input {
+			if result[i:i+1] != s {
+				t.Fatalf("Expected order to be preserved, first mismatch at %d", i)
+			}
+		}
+	}
+}
+
+func TestReduceChan(t *testing.T) {
+	ch := make(chan int)
+	go func() {
+		for i := 1; i <= 10000; i++ {
+			ch <- i
+		}
+		close(ch)
+	}()
+
+	sumFunc := func(a, b int) (int, error) { return a + b, nil }
+	result, err := ReduceChan(ch, sumFunc, Options{}.WithWorkers(2))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result != 50005000 {
+		t.Errorf("Expected 50005000, got %d", result)
+	}
+}
+
+func TestReduceSeq_Error(t *testing.T) {
+	input := make([]int, 2*streamChunkSize)
+	for i := range input {
+		input[i] = i
+	}
+	errFunc := func(a, b int) (int, error) {
+		if a == 100 || b == 100 {
+			return 0, errors.New("fail on 100")
+		}
+		return a + b, nil
+	}
+
+	consumed := 0
+	seq := func(yield func(int) bool) {
+		for _, x := range input {
+			consumed++
+			if !yield(x) {
+				return
+			}
+		}
+	}
+	_, err := ReduceSeq(seq, errFunc, Options{}.WithWorkers(2))
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+	if consumed > streamChunkSize+1 {
+		t.Errorf("Expected consumption to stop after the failing chunk, consumed %d", consumed)
+	}
+}
+
+func TestReduceSeq_Absorbing(t *testing.T) {
+	consumed := 0
+	seq := func(yield func(int) bool) {
+		for i := 0; ; i++ {
+			consumed++
+			x := 1
+			if i == 10 {
+				x = 0
+			}
+			if !yield(x) {
+				return
+			}
+		}
+	}
+	prodFunc := func(a, b int) (int, error) { return a * b, nil }
+	opts := WithAbsorbing(Options{}.WithWorkers(2), func(x int) bool { return x == 0 })
+
+	result, err := ReduceSeq(seq, prodFunc, opts)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result != 0 {
+		t.Errorf("Expected 0, got %d", result)
+	}
+	if consumed > streamChunkSize+1 {
+		t.Errorf("Expected consumption to stop once absorbed, consumed %d", consumed)
+	}
+}
+
+func TestReduceSeq_DeterministicAcrossWorkers(t *testing.T) {
+	input := floatInput(3*streamChunkSize + 123)
+	sumFunc := func(a, b float64) (float64, error) { return a + b, nil }
+
+	expected, err := ReduceSeq(slices.Values(input), sumFunc, Options{}.WithWorkers(1).Deterministic(true))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, workers := range []int{2, 5, 32} {
+		result, err := ReduceSeq(slices.Values(input), sumFunc, Options{}.WithWorkers(workers).Deterministic(true))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if math.Float64bits(result) != math.Float64bits(expected) {
+			t.Errorf("Workers=%d: expected %v, got %v", workers, expected, result)
+		}
+	}
+}
+
+func TestReduceSeq_ReduceErrorInputs(t *testing.T) {
+	input := make([]int, 3*streamChunkSize)
+	for i := range input {
+		input[i] = i
+	}
+	target := 2*streamChunkSize + 11
+	failFunc := func(a, b int) (int, error) {
+		if b == target {
+			return 0, errors.New("fail")
+		}
+		return a + b, nil
+	}
+
+	_, err := ReduceSeq(slices.Values(input), failFunc, Options{}.WithWorkers(2))
+	var reduceErr *ReduceError
+	if !errors.As(err, &reduceErr) {
+		t.Fatalf("Expected *ReduceError, got %v", err)
+	}
+	right := reduceErr.Operands[1]
+	if len(right) != 1 || right[0] != (Range{Lo: target, Hi: target + 1}) {
+		t.Errorf("Expected right operand relative to the stream, got %v", right)
+	}
+}
@@ -7,6 +7,9 @@
 // - The order of results is preserved, but the processing is done in parallel.
 // - If AbortOnError is true, the first returned error will stop processing.
 //   If multiple errors occur, only the first will be returned, and the rest will be ignored.
-// - The reduction function in ParallelReduce should be associative -- Order is *not* guaranteed.
+// - The reduction function in ParallelReduce should be associative. Calls run in any order, but always
+//   combine adjacent operands in input order, as do ParallelReduceK and ReduceSeq, so f need not be
+//   commutative; Deterministic(true) also makes the result bit-identical whatever the worker count.
+//   Only Commutative(true) gives up operand order; ReduceSeq and ReduceChan ignore it.
 
 package toil
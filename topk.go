@@ -0,0 +1,111 @@
+package toil
+
+import (
+	"cmp"
+	"slices"
+)
+
+// ParallelTopK returns the k greatest items of v according to less, greatest first. If v has fewer than
+// k items, all of them are returned. Every worker keeps the k greatest items it has seen in its own heap,
+// and the heaps are merged at the end. The order of items comparing equal is not specified.
+func ParallelTopK[T any](v []T, k int, less func(a, b T) bool, opts Options) []T {
+	if k <= 0 {
+		return []T{}
+	}
+	opts = opts.resolve(len(v))
+
+	heaps := make([][]T, opts.workers)
+	_ = forEachBlock(opts.split(len(v)), opts, func(worker int, b block) error {
+		h := heaps[worker]
+		for _, x := range v[b.lo:b.hi] {
+			if len(h) < k {
+				h = append(h, x)
+				siftUp(h, len(h)-1, less)
+			} else if less(h[0], x) {
+				// x beats the smallest of the current top k
+				h[0] = x
+				siftDown(h, 0, less)
+			}
+		}
+		heaps[worker] = h
+		return nil
+	})
+
+	top := concat(heaps)
+	slices.SortFunc(top, func(a, b T) int {
+		switch {
+		case less(b, a):
+			return -1
+		case less(a, b):
+			return 1
+		}
+		return 0
+	})
+	return top[:min(k, len(top))]
+}
+
+// siftUp restores the min-heap order of h after h[i] was added.
+func siftUp[T any](h []T, i int, less func(a, b T) bool) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !less(h[i], h[parent]) {
+			return
+		}
+		h[i], h[parent] = h[parent], h[i]
+		i = parent
+	}
+}
+
+// siftDown restores the min-heap order of h after h[i] was replaced.
+func siftDown[T any](h []T, i int, less func(a, b T) bool) {
+	for {
+		smallest := i
+		if left := 2*i + 1; left < len(h) && less(h[left], h[smallest]) {
+			smallest = left
+		}
+		if right := 2*i + 2; right < len(h) && less(h[right], h[smallest]) {
+			smallest = right
+		}
+		if smallest == i {
+			return
+		}
+		h[i], h[smallest] = h[smallest], h[i]
+		i = smallest
+	}
+}
+
+// ParallelMinMax returns the smallest and greatest items of v. Every worker tracks its own extremes,
+// which are combined at the end. ok is false if v is empty.
+func ParallelMinMax[T cmp.Ordered](v []T, opts Options) (lo, hi T, ok bool) {
+	if len(v) == 0 {
+		return lo, hi, false
+	}
+	opts = opts.resolve(len(v))
+
+	type extremes struct {
+		lo, hi T
+		ok     bool
+	}
+	partials := make([]extremes, opts.workers)
+	_ = forEachBlock(opts.split(len(v)), opts, func(worker int, b block) error {
+		p := partials[worker]
+		if !p.ok {
+			p = extremes{lo: v[b.lo], hi: v[b.lo], ok: true}
+		}
+		for _, x := range v[b.lo:b.hi] {
+			p.lo = min(p.lo, x)
+			p.hi = max(p.hi, x)
+		}
+		partials[worker] = p
+		return nil
+	})
+
+	lo, hi = v[0], v[0]
+	for _, p := range partials {
+		if p.ok {
+			lo = min(lo, p.lo)
+			hi = max(hi, p.hi)
+		}
+	}
+	return lo, hi, true
+}
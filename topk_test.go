@@ -0,0 +1,52 @@
+package toil
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestParallelTopK(t *testing.T) {
+	r := rand.New(rand.NewPCG(7, 8))
+	input := make([]int, 10000)
+	for i := range input {
+		input[i] = r.IntN(1000000)
+	}
+	expected := slices.Clone(input)
+	slices.Sort(expected)
+	slices.Reverse(expected)
+
+	less := func(a, b int) bool { return a < b }
+	for _, k := range []int{1, 10, 100} {
+		result := ParallelTopK(input, k, less, Options{}.WithWorkers(4))
+		if !slices.Equal(result, expected[:k]) {
+			t.Errorf("k=%d: expected %v, got %v", k, expected[:k], result)
+		}
+	}
+}
+
+func TestParallelTopK_FewerThanK(t *testing.T) {
+	less := func(a, b string) bool { return a < b }
+	result := ParallelTopK([]string{"b", "c", "a"}, 10, less, Options{}.WithWorkers(2))
+	if !slices.Equal(result, []string{"c", "b", "a"}) {
+		t.Errorf("Expected [c b a], got %v", result)
+	}
+	if result := ParallelTopK([]string{"a"}, 0, less, Options{}); len(result) != 0 {
+		t.Errorf("Expected no items for k=0, got %v", result)
+	}
+}
+
+func TestParallelMinMax(t *testing.T) {
+	input := make([]float64, 5000)
+	for i := range input {
+		input[i] = float64((i*7919)%5000) - 2500
+	}
+	lo, hi, ok := ParallelMinMax(input, Options{}.WithWorkers(3))
+	if !ok || lo != -2500 || hi != 2499 {
+		t.Errorf("Expected -2500 and 2499, got %v, %v, %v", lo, hi, ok)
+	}
+
+	if _, _, ok := ParallelMinMax([]int{}, Options{}); ok {
+		t.Errorf("Expected ok to be false for empty input")
+	}
+}
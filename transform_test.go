@@ -383,3 +383,138 @@ func BenchmarkToil_Sized(b *testing.B) {
 		})
 	}
 }
+
+func TestToilOptions_WithGrainSize(t *testing.T) {
+	opts := Options{}
+	newOpts := opts.WithGrainSize(64).WithSequentialCutoff(100)
+
+	if newOpts.grainSize != 64 || newOpts.sequentialCutoff != 100 {
+		t.Errorf("Expected grain size 64 and cutoff 100, got %d and %d", newOpts.grainSize, newOpts.sequentialCutoff)
+	}
+	if opts.grainSize != 0 || opts.sequentialCutoff != 0 {
+		t.Errorf("Original options should not be modified, got %+v", opts)
+	}
+}
+
+func TestToil_GrainSize(t *testing.T) {
+	input := make([]int, 1000)
+	for i := range input {
+		input[i] = i
+	}
+	square := func(x int) (int, error) {
+		return x * x, nil
+	}
+
+	for _, grain := range []int{-1, 0, 1, 7, 64, 1000, 5000} {
+		results, err := ParallelTransform(input, square, Options{}.WithWorkers(4).WithGrainSize(grain))
+		if err != nil {
+			t.Fatalf("grain %d: unexpected error: %v", grain, err)
+		}
+		for i, result := range results {
+			if result != i*i {
+				t.Fatalf("grain %d: expected result[%d] to be %d, got %d", grain, i, i*i, result)
+			}
+		}
+	}
+}
+
+func TestToil_GrainSizeWithError(t *testing.T) {
+	input := []int{1, 2, 3, 4, 5, 6, 7, 8}
+	errorOnEven := func(x int) (int, error) {
+		if x%2 == 0 {
+			return 0, errors.New("even number error")
+		}
+		return x * 2, nil
+	}
+
+	// Errors must not stop the rest of a chunk unless stopping on error
+	results, err := ParallelTransform(input, errorOnEven, Options{}.WithWorkers(2).WithGrainSize(4))
+	if err == nil {
+		t.Fatal("Expected error but got none")
+	}
+	expected := []int{2, 0, 6, 0, 10, 0, 14, 0}
+	for i, result := range results {
+		if result != expected[i] {
+			t.Errorf("Expected result[%d] to be %d, got %d", i, expected[i], result)
+		}
+	}
+
+	results, err = ParallelTransform(input, errorOnEven, Options{}.WithWorkers(2).WithGrainSize(4).StopOnError(true))
+	if err == nil || results != nil {
+		t.Errorf("Expected nil results and an error, got %v and %v", results, err)
+	}
+}
+
+func TestToil_SequentialCutoff(t *testing.T) {
+	input := make([]int, 100)
+	for i := range input {
+		input[i] = i
+	}
+
+	// Below the cutoff, items are transformed in order on the calling goroutine
+	var order []int
+	record := func(x int) (int, error) {
+		order = append(order, x)
+		return x, nil
+	}
+	opts := Options{}.WithWorkers(8).WithGrainSize(1).WithSequentialCutoff(len(input))
+	if _, err := ParallelTransform(input, record, opts); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(order) != len(input) {
+		t.Fatalf("Expected %d calls, got %d", len(input), len(order))
+	}
+	for i, x := range order {
+		if x != i {
+			t.Fatalf("Expected call %d to transform %d, got %d", i, i, x)
+		}
+	}
+
+	// Above it, workers run concurrently again
+	var mu sync.Mutex
+	current, maxConcurrent := 0, 0
+	slow := func(x int) (int, error) {
+		mu.Lock()
+		current++
+		maxConcurrent = max(maxConcurrent, current)
+		mu.Unlock()
+		time.Sleep(time.Millisecond)
+		mu.Lock()
+		current--
+		mu.Unlock()
+		return x, nil
+	}
+	opts = Options{}.WithWorkers(4).WithGrainSize(1).WithSequentialCutoff(len(input) - 1)
+	if _, err := ParallelTransform(input, slow, opts); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if maxConcurrent < 2 {
+		t.Errorf("Expected concurrent calls above the cutoff, got at most %d", maxConcurrent)
+	}
+}
+
+func BenchmarkToil_GrainSize(b *testing.B) {
+	input := make([]int, 100000)
+	for i := range input {
+		input[i] = i + 1
+	}
+
+	square := func(x int) (int, error) {
+		return x * x, nil
+	}
+
+	for _, grain := range []int{1, 16, 256, 0} {
+		name := fmt.Sprintf("Grain%d", grain)
+		if grain == 0 {
+			name = "GrainAuto"
+		}
+		b.Run(name, func(b *testing.B) {
+			opts := Options{}.WithWorkers(runtime.NumCPU()).WithGrainSize(grain)
+			for b.Loop() {
+				if _, err := ParallelTransform(input, square, opts); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}